@@ -0,0 +1,134 @@
+// Offline scenario linter, meant for deploy pipeline.
+// Registers all drivers against MDB without devices, resolves every scenario in config.
+package config_check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/cmd/vender/subcmd"
+	"github.com/temoto/vender/hardware"
+	"github.com/temoto/vender/hardware/mdb"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/money"
+	"github.com/temoto/vender/internal/state"
+	"github.com/temoto/vender/log2"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+var Mod = subcmd.Mod{Name: "config-check", Main: Main}
+
+func Main(ctx context.Context, config *state.Config) error {
+	g := state.GetGlobal(ctx)
+	g.Log.SetLevel(log2.LInfo)
+	Offline(ctx, config)
+
+	issues := Check(ctx, config)
+	for _, err := range issues {
+		fmt.Println(err.Error())
+	}
+	if len(issues) != 0 {
+		return errors.Errorf("config-check issues=%d", len(issues))
+	}
+	fmt.Println("config-check ok")
+	return nil
+}
+
+// Offline disables telemetry, display, input, persistent storage and MDB hardware.
+// Drivers still register their actions. Call before g.Init.
+func Offline(ctx context.Context, config *state.Config) {
+	g := state.GetGlobal(ctx)
+	g.Tele = tele_api.Noop{}
+	config.Tele.Enabled = false
+	config.Hardware.Display.Framebuffer = ""
+	config.Hardware.HD44780.Enable = false
+	config.Hardware.Input.EvendKeyboard.Enable = false
+	config.Hardware.Input.DevInputEvent.Enable = false
+	config.Engine.Inventory.Persist = false
+	config.Money.Persist = false
+	for i := range config.Hardware.XXX_Devices {
+		config.Hardware.XXX_Devices[i].Required = false
+	}
+	g.Hardware.Mdb.Bus = mdb.NewMockBusOffline(g.Log)
+}
+
+// Check initializes global state and returns all config problems found.
+func Check(ctx context.Context, config *state.Config) []error {
+	g := state.GetGlobal(ctx)
+	issues := make([]error, 0)
+	addIssue := func(err error) {
+		if err != nil {
+			issues = append(issues, err)
+		}
+	}
+
+	addIssue(errors.Annotate(g.Init(ctx, config), "init"))
+	addIssue(errors.Annotate(hardware.Enum(ctx), "hardware enum"))
+	ms := &money.MoneySystem{}
+	addIssue(errors.Annotate(ms.Start(ctx), "money system Start()"))
+
+	c := engine.NewChecker(g.Engine)
+	aliasNames := make([]string, len(config.Engine.Aliases))
+	for i, x := range config.Engine.Aliases {
+		aliasNames[i] = x.Name
+	}
+	c.Aliases(aliasNames...)
+	for _, x := range config.Engine.Aliases {
+		c.Check("alias."+x.Name, x.Doer, strings.HasSuffix(x.Name, "(?)"))
+	}
+	for _, x := range config.Engine.Menu.Items {
+		c.Check("menu."+x.Code, x.Doer, false)
+	}
+	for _, x := range config.Engine.Jobs {
+		c.Check("job."+x.Name, x.Doer, false)
+	}
+	for _, x := range config.Engine.Hooks {
+		c.Check("hook."+x.Event, x.Doer, false)
+	}
+	hooks := []struct {
+		tag  string
+		list []string
+	}{
+		{"on_boot", config.Engine.OnBoot},
+		{"on_menu_error", config.Engine.OnMenuError},
+		{"on_service_begin", config.Engine.OnServiceBegin},
+		{"on_service_end", config.Engine.OnServiceEnd},
+		{"on_front_begin", config.Engine.OnFrontBegin},
+		{"on_broken", config.Engine.OnBroken},
+	}
+	for _, h := range hooks {
+		for i, text := range h.list {
+			tag := fmt.Sprintf("%s:%d", h.tag, i)
+			d, err := g.Engine.ParseText(tag, text)
+			if err != nil {
+				addIssue(err)
+				continue
+			}
+			c.Check(tag, d, false)
+		}
+	}
+	for _, x := range config.UI.Service.Tests {
+		tag := "ui.service.test." + x.Name
+		d, err := g.Engine.ParseText(tag, x.Scenario)
+		if err != nil {
+			addIssue(err)
+			continue
+		}
+		c.Check(tag, d, false)
+	}
+
+	// register_add defines stock action, check it after usage
+	for _, x := range config.Engine.Inventory.Stocks {
+		if !(c.Used("stock."+x.Name+".") || c.Used("stock."+x.Name+"(") || c.Used("add."+x.Name+"(")) {
+			addIssue(errors.Errorf("stock=%s unused", x.Name))
+		}
+	}
+	for _, x := range config.Engine.Inventory.Stocks {
+		if x.RegisterAdd != "" {
+			c.Check("stock."+x.Name+".register_add", g.Engine.Resolve("add."+x.Name+"(?)"), true)
+		}
+	}
+	return append(issues, c.Issues()...)
+}
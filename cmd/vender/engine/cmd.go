@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,9 +26,17 @@ const usage = `syntax: commands separated by whitespace
 - ACTION   execute engine action
 - /sN      pause N milliseconds
 - /mXX...  MDB send XX... in hex, receive
+- help ACTION  describe action: device, arguments, customer safe
+- /export  print all actions with description as JSON to stdout
+- /journal[=STOCK]  print last inventory changes, optionally of one stock
 
 (meta)
 - /loop=N  repeat N times all commands on this line
+
+(stock hw_table calibration)
+- stock.NAME.calibrate(HW)  run hardware with raw value HW
+- stock.NAME.measured(X)    record measured output X of last calibrate
+- stock.NAME.calibration    apply recorded points, print hw_table config
 `
 
 var Mod = subcmd.Mod{Name: "engine-cli", Main: Main}
@@ -55,7 +64,7 @@ func Main(ctx context.Context, config *state.Config) error {
 		g.Inventory.Iter(func(stock *inventory.Stock) {
 			current := stock.Value()
 			g.Log.Debugf("- source=%s value=%f", stock.Name, current)
-			stock.Set(current + float32(arg))
+			stock.SetBy(inventory.SourceCli, current+float32(arg))
 		})
 		return nil
 	}})
@@ -91,6 +100,7 @@ func newCompleter(ctx context.Context) func(d prompt.Document) []prompt.Suggest
 
 func newExecutor(ctx context.Context) func(string) {
 	g := state.GetGlobal(ctx)
+	ctx = inventory.WithSource(ctx, inventory.SourceCli)
 
 	return func(line string) {
 		d, err := parseLine(ctx, line)
@@ -123,6 +133,49 @@ var doUsage = engine.Func{F: func(ctx context.Context) error {
 	return nil
 }}
 
+var doExport = engine.Func{F: func(ctx context.Context) error {
+	return engine.GetGlobal(ctx).ExportJSON(os.Stdout)
+}}
+
+const journalLimit = 50
+
+func newJournal(stock string) engine.Doer {
+	return engine.Func{F: func(ctx context.Context) error {
+		g := state.GetGlobal(ctx)
+		changes, err := g.Inventory.Journal.Query(stock, time.Time{}, journalLimit)
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			g.Log.Infof("%s stock=%s reason=%s source=%s %g -> %g",
+				c.Time.Format(time.RFC3339), c.Stock, c.Reason, c.Source, c.Before, c.After)
+		}
+		return nil
+	}}
+}
+
+func newHelp(actions []string) engine.Doer {
+	return engine.Func{F: func(ctx context.Context) error {
+		g := state.GetGlobal(ctx)
+		for _, action := range actions {
+			info, ok := g.Engine.Describe(action)
+			if !ok {
+				g.Log.Errorf("action=%s not registered", action)
+				continue
+			}
+			g.Log.Infof("%s (%s) %s", info.Name, info.Kind, info.Description)
+			if info.Device != "" {
+				g.Log.Infof("  device=%s", info.Device)
+			}
+			for _, p := range info.Params {
+				g.Log.Infof("  param=%s unit=%s min=%d max=%d optional=%t default=%d", p.String(), p.Unit, p.Min, p.Max, p.Optional, p.Default)
+			}
+			g.Log.Infof("  customer safe=%t", info.Safe)
+		}
+		return nil
+	}}
+}
+
 func newTx(request mdb.Packet) engine.Doer {
 	return engine.Func{Name: "mdb:" + request.Format(), F: func(ctx context.Context) error {
 		g := state.GetGlobal(ctx)
@@ -159,6 +212,10 @@ func parseLine(ctx context.Context, line string) (engine.Doer, error) {
 		return engine.Nothing{}, nil
 	}
 
+	if words[0] == "help" && len(words) > 1 {
+		return newHelp(words[1:]), nil
+	}
+
 	// pre-parse special commands
 	loopn := uint(0)
 	wordsRest := make([]string, 0, len(words))
@@ -168,6 +225,12 @@ func parseLine(ctx context.Context, line string) (engine.Doer, error) {
 			fallthrough
 		case word == "/help":
 			return doUsage, nil
+		case word == "/export":
+			return doExport, nil
+		case word == "/journal":
+			return newJournal(""), nil
+		case strings.HasPrefix(word, "/journal="):
+			return newJournal(word[9:]), nil
 		case strings.HasPrefix(word, "/loop="):
 			if loopn != 0 {
 				return nil, errors.Errorf("multiple loop commands, expected at most one")
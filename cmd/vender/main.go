@@ -12,8 +12,10 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/temoto/vender/cmd/vender/config_check"
 	cmd_engine "github.com/temoto/vender/cmd/vender/engine"
 	"github.com/temoto/vender/cmd/vender/mdb"
+	"github.com/temoto/vender/cmd/vender/simulate"
 	"github.com/temoto/vender/cmd/vender/subcmd"
 	cmd_tele "github.com/temoto/vender/cmd/vender/tele"
 	"github.com/temoto/vender/cmd/vender/ui"
@@ -28,6 +30,8 @@ var log = log2.NewStderr(log2.LDebug)
 var modules = []subcmd.Mod{
 	vmc.BrokenMod,
 	cmd_engine.Mod,
+	config_check.Mod,
+	simulate.Mod,
 	mdb.Mod,
 	cmd_tele.Mod,
 	ui.Mod,
@@ -0,0 +1,74 @@
+// Dry run of every menu item without hardware.
+// Device actions are replaced by timing models from config `engine.simulate`,
+// prints estimated duration and stock spend of each item.
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/cmd/vender/config_check"
+	"github.com/temoto/vender/cmd/vender/subcmd"
+	"github.com/temoto/vender/hardware"
+	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/money"
+	"github.com/temoto/vender/internal/state"
+	"github.com/temoto/vender/log2"
+)
+
+var Mod = subcmd.Mod{Name: "simulate", Main: Main}
+
+func Main(ctx context.Context, config *state.Config) error {
+	g := state.GetGlobal(ctx)
+	g.Log.SetLevel(log2.LInfo)
+	config_check.Offline(ctx, config)
+
+	models := make([]engine.SimModel, 0, len(config.Engine.Simulate.Models))
+	for _, x := range config.Engine.Simulate.Models {
+		m, err := engine.ParseSimModel(x.Match, x.Duration, x.PerArg, x.Result)
+		if err != nil {
+			return errors.Annotate(err, "simulate")
+		}
+		models = append(models, m)
+	}
+	sim := engine.NewSimulation(models...)
+
+	if err := g.Init(ctx, config); err != nil {
+		return errors.Annotate(err, "init")
+	}
+	// devices are offline, only registered actions matter
+	if err := hardware.Enum(ctx); err != nil {
+		g.Log.Debugf("simulate hardware enum err=%v", err)
+	}
+	ms := &money.MoneySystem{}
+	if err := ms.Start(ctx); err != nil {
+		g.Log.Debugf("simulate money system Start() err=%v", err)
+	}
+	// untracked stocks pass checks, spend is recorded by simulation
+	g.Inventory.DisableAll()
+
+	errs := make([]error, 0)
+	for _, item := range config.Engine.Menu.Items {
+		simCtx, result := engine.WithSimulation(ctx, sim)
+		err := g.Engine.Exec(simCtx, item.Doer)
+		fmt.Printf("%s duration=%v\n", item.String(), result.Duration())
+		spend := result.Spend()
+		names := make([]string, 0, len(spend))
+		for name := range spend {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  stock=%s spend=%g hw=%g\n", name, spend[name].Spend, spend[name].Hw)
+		}
+		if err != nil {
+			err = errors.Annotatef(err, "menu.%s", item.Code)
+			fmt.Printf("  error: %v\n", err)
+			errs = append(errs, err)
+		}
+	}
+	return helpers.FoldErrors(errs)
+}
@@ -46,10 +46,13 @@ func VmcMain(ctx context.Context, config *state.Config) error {
 		return errors.Annotate(err, "ui Init()")
 	}
 
+	g.RunJobs(ctx)
+
 	subcmd.SdNotify(daemon.SdNotifyReady)
 	g.Log.Debugf("VMC init complete")
 
 	ui.Loop(ctx)
+	g.Inventory.Stop()
 	return nil
 }
 
@@ -90,5 +93,6 @@ func BrokenMain(ctx context.Context, config *state.Config) error {
 	display.SetLines(g.Config.UI.Front.MsgStateBroken, "")
 	g.Error(errors.Errorf("critical daemon broken mode"))
 	g.Alive.Wait()
+	g.Inventory.Stop()
 	return nil
 }
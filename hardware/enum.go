@@ -4,25 +4,51 @@ import (
 	"context"
 	"sync"
 
+	"github.com/temoto/vender/hardware/mdb"
 	"github.com/temoto/vender/hardware/mdb/bill"
+	"github.com/temoto/vender/hardware/mdb/cashless"
 	"github.com/temoto/vender/hardware/mdb/coin"
 	"github.com/temoto/vender/hardware/mdb/evend"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/internal/state"
 )
 
 func Enum(ctx context.Context) error {
-	const N = 3
+	g := state.GetGlobal(ctx)
+	if bus, err := g.Mdb(); err == nil && bus != nil {
+		bus.StateChange = deviceEvents(ctx, g.Engine)
+	}
+
+	const N = 4
 	errch := make(chan error, N+1)
 	wg := sync.WaitGroup{}
 	wg.Add(N)
 
 	go helpers.WrapErrChan(&wg, errch, func() error { return bill.Enum(ctx) })
 	go helpers.WrapErrChan(&wg, errch, func() error { return coin.Enum(ctx) })
+	go helpers.WrapErrChan(&wg, errch, func() error { return cashless.Enum(ctx) })
 	go helpers.WrapErrChan(&wg, errch, func() error { return evend.Enum(ctx) })
 
 	wg.Wait()
-	errch <- state.GetGlobal(ctx).CheckDevices()
+	errch <- g.CheckDevices()
 	close(errch)
 	return helpers.FoldErrChan(errch)
 }
+
+// deviceEvents emits EventDeviceOffline when online device stops responding
+// and EventDeviceRecovered when it responds again.
+func deviceEvents(ctx context.Context, e *engine.Engine) func(*mdb.Device, mdb.DeviceState, mdb.DeviceState) {
+	return func(d *mdb.Device, old, new mdb.DeviceState) {
+		name := ""
+		switch {
+		case old.Online() && new == mdb.DeviceOffline:
+			name = engine.EventDeviceOffline
+		case old == mdb.DeviceOffline && new.Online():
+			name = engine.EventDeviceRecovered
+		default:
+			return
+		}
+		e.Emit(ctx, engine.Event{Name: name, Subject: d.Name(), Vars: engine.Vars{"addr": engine.Arg(d.Address)}})
+	}
+}
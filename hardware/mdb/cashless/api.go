@@ -0,0 +1,53 @@
+package cashless
+
+import (
+	"context"
+
+	"github.com/temoto/alive/v2"
+	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/hardware/money"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/state"
+)
+
+const deviceName = "cashless"
+
+func Enum(ctx context.Context) error {
+	g := state.GetGlobal(ctx)
+	dev := &CashlessReader{}
+	return g.RegisterDevice(deviceName, dev, func() error { return dev.init(ctx) })
+}
+
+// Cashlesser session credit is reported to Run callback as StatusCredit,
+// session end as StatusDisabled, customer cancel as StatusReturnRequest.
+type Cashlesser interface {
+	AcceptMax(currency.Amount) engine.Doer
+	Run(context.Context, *alive.Alive, func(money.PollItem) bool)
+	VendRequest(ctx context.Context, price currency.Amount, item uint16) (currency.Amount, error)
+	VendSuccess(item uint16) engine.Doer
+	VendFailure() engine.Doer
+	SessionComplete() engine.Doer
+	Revalue(currency.Amount) engine.Doer
+}
+
+var _ Cashlesser = &CashlessReader{}
+var _ Cashlesser = Stub{}
+
+type Stub struct{}
+
+func (Stub) AcceptMax(currency.Amount) engine.Doer { return engine.Nothing{} }
+
+func (Stub) Run(ctx context.Context, alive *alive.Alive, fun func(money.PollItem) bool) {
+	if alive != nil {
+		alive.Done()
+	}
+}
+
+func (Stub) VendRequest(context.Context, currency.Amount, uint16) (currency.Amount, error) {
+	return 0, ErrVendDenied
+}
+
+func (Stub) VendSuccess(uint16) engine.Doer      { return engine.Nothing{} }
+func (Stub) VendFailure() engine.Doer            { return engine.Nothing{} }
+func (Stub) SessionComplete() engine.Doer        { return engine.Nothing{} }
+func (Stub) Revalue(currency.Amount) engine.Doer { return engine.Nothing{} }
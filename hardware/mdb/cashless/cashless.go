@@ -0,0 +1,436 @@
+// Package cashless incapsulates work with MDB cashless devices (card readers).
+package cashless
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/temoto/alive/v2"
+	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/hardware/mdb"
+	"github.com/temoto/vender/hardware/money"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/state"
+)
+
+const (
+	AddressCashless1 uint8 = 0x10
+	AddressCashless2 uint8 = 0x60
+)
+
+// Command offsets from device address.
+const (
+	cmdSetup   byte = 0x01
+	cmdPoll    byte = 0x02
+	cmdVend    byte = 0x03
+	cmdReader  byte = 0x04
+	cmdRevalue byte = 0x05
+)
+
+const (
+	DefaultResponseTimeout = 5 * time.Second
+	DefaultVendTimeout     = 60 * time.Second // customer may need to confirm payment on reader
+)
+
+// Item number for menu codes which are not numeric.
+const ItemUnknown uint16 = 0xffff
+
+// Session funds reported by reader as unknown.
+const FundsUnknown = currency.Nominal(currency.MaxAmount)
+
+type CashlessReader struct { //nolint:maligned
+	mdb.Device
+	pollmu        sync.Mutex // isolate active/idle polling
+	configScaling uint32
+	enabled       uint32 // atomic
+
+	// parsed from SETUP
+	featureLevel    uint8
+	countryCode     uint16
+	scaleFactor     uint8
+	decimalPlaces   uint8
+	responseTimeout time.Duration
+}
+
+var (
+	ErrVendDenied    = errors.New("Vend Denied")
+	ErrRevalueDenied = errors.New("Revalue Denied")
+	ErrOutOfSequence = errors.New("Command Out Of Sequence")
+)
+
+const (
+	StatusJustReset            byte = 0x00
+	StatusReaderConfig         byte = 0x01
+	StatusDisplayRequest       byte = 0x02
+	StatusBeginSession         byte = 0x03
+	StatusSessionCancelRequest byte = 0x04
+	StatusVendApproved         byte = 0x05
+	StatusVendDenied           byte = 0x06
+	StatusEndSession           byte = 0x07
+	StatusCancelled            byte = 0x08
+	StatusPeripheralID         byte = 0x09
+	StatusMalfunction          byte = 0x0a
+	StatusOutOfSequence        byte = 0x0b
+	StatusRevalueApproved      byte = 0x0d
+	StatusRevalueDenied        byte = 0x0e
+	StatusRevalueLimit         byte = 0x0f
+	StatusTimeDateRequest      byte = 0x11
+)
+
+func (self *CashlessReader) init(ctx context.Context) error {
+	const tag = deviceName + ".init"
+	g := state.GetGlobal(ctx)
+	mdbus, err := g.Mdb()
+	if err != nil {
+		return errors.Annotate(err, tag)
+	}
+	config := g.Config.Hardware.Mdb.Cashless
+	addr := AddressCashless1
+	switch config.Number {
+	case 0, 1:
+	case 2:
+		addr = AddressCashless2
+	default:
+		return errors.NotValidf("%s config number=%d", tag, config.Number)
+	}
+	self.Device.Init(mdbus, addr, deviceName, binary.BigEndian)
+	self.Device.PacketPoll = self.request(cmdPoll)
+	self.configScaling = 100
+	if config.ScalingFactor != 0 {
+		self.configScaling = uint32(config.ScalingFactor)
+	}
+	self.responseTimeout = DefaultResponseTimeout
+
+	self.Device.DoInit = self.newIniter()
+
+	// TODO remove IO from Init()
+	if err = g.Engine.Exec(ctx, self.Device.DoInit); err != nil {
+		return errors.Annotate(err, tag)
+	}
+	return nil
+}
+
+// AcceptMax enables reader for any non-zero amount, card funds are checked by vend request.
+func (self *CashlessReader) AcceptMax(max currency.Amount) engine.Doer {
+	enable := max != 0
+	tag := deviceName + ".reader-disable"
+	request := self.request(cmdReader, 0x00)
+	if enable {
+		tag = deviceName + ".reader-enable"
+		request = self.request(cmdReader, 0x01)
+	}
+	return engine.Func0{Name: tag, F: func() error {
+		if self.Enabled() == enable {
+			return nil
+		}
+		if err := self.Device.TxKnown(request, nil); err != nil {
+			return errors.Annotate(err, tag)
+		}
+		self.setEnabled(enable)
+		return nil
+	}}
+}
+
+func (self *CashlessReader) Enabled() bool { return atomic.LoadUint32(&self.enabled) != 0 }
+func (self *CashlessReader) setEnabled(b bool) {
+	u := uint32(0)
+	if b {
+		u = 1
+	}
+	atomic.StoreUint32(&self.enabled, u)
+}
+
+func (self *CashlessReader) Run(ctx context.Context, alive *alive.Alive, fun func(money.PollItem) bool) {
+	var stopch <-chan struct{}
+	if alive != nil {
+		defer alive.Done()
+		stopch = alive.StopChan()
+	}
+	pd := mdb.PollDelay{}
+	var active bool
+	var err error
+	again := true
+	for again {
+		response := mdb.Packet{}
+		self.pollmu.Lock()
+		err = self.Device.TxKnown(self.Device.PacketPoll, &response)
+		self.pollmu.Unlock()
+		active = false
+		if err == nil {
+			active = response.Len() != 0
+			self.parse(response.Bytes(), fun)
+		}
+		again = (alive != nil) && (alive.IsRunning()) && pd.Delay(&self.Device, active, err != nil, stopch)
+	}
+}
+
+// VendRequest asks reader to charge price from session funds, returns approved amount.
+func (self *CashlessReader) VendRequest(ctx context.Context, price currency.Amount, item uint16) (currency.Amount, error) {
+	const tag = deviceName + ".vend-request"
+	buf := [5]byte{0x00}
+	self.Device.ByteOrder.PutUint16(buf[1:], self.fromAmount(price))
+	self.Device.ByteOrder.PutUint16(buf[3:], item)
+	var approved currency.Amount
+	var result error
+	err := self.txWait(ctx, tag, self.request(cmdVend, buf[:]...), DefaultVendTimeout, func(pi money.PollItem) bool {
+		switch pi.HardwareCode {
+		case StatusVendApproved:
+			approved = currency.Amount(pi.DataNominal)
+			return true
+		case StatusVendDenied, StatusEndSession:
+			result = ErrVendDenied
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return 0, err
+	}
+	self.Log.Debugf("%s price=%s item=%d approved=%s err=%v", tag, price.FormatCtx(ctx), item, approved.FormatCtx(ctx), result)
+	return approved, result
+}
+
+func (self *CashlessReader) VendSuccess(item uint16) engine.Doer {
+	const tag = deviceName + ".vend-success"
+	buf := [3]byte{0x02}
+	self.Device.ByteOrder.PutUint16(buf[1:], item)
+	request := self.request(cmdVend, buf[:]...)
+	return engine.Func0{Name: tag, F: func() error {
+		return errors.Annotate(self.Device.TxKnown(request, nil), tag)
+	}}
+}
+
+// VendFailure makes reader refund approved amount.
+func (self *CashlessReader) VendFailure() engine.Doer {
+	const tag = deviceName + ".vend-failure"
+	request := self.request(cmdVend, 0x03)
+	return engine.Func0{Name: tag, F: func() error {
+		return errors.Annotate(self.Device.TxKnown(request, nil), tag)
+	}}
+}
+
+// SessionComplete ends session and waits for END SESSION.
+func (self *CashlessReader) SessionComplete() engine.Doer {
+	const tag = deviceName + ".session-complete"
+	request := self.request(cmdVend, 0x04)
+	return engine.Func{Name: tag, F: func(ctx context.Context) error {
+		return self.txWait(ctx, tag, request, self.responseTimeout, func(pi money.PollItem) bool {
+			return pi.HardwareCode == StatusEndSession
+		})
+	}}
+}
+
+// Revalue returns amount to payment media, valid during session.
+func (self *CashlessReader) Revalue(amount currency.Amount) engine.Doer {
+	const tag = deviceName + ".revalue"
+	buf := [3]byte{0x00}
+	self.Device.ByteOrder.PutUint16(buf[1:], self.fromAmount(amount))
+	request := self.request(cmdRevalue, buf[:]...)
+	return engine.Func{Name: tag, F: func(ctx context.Context) error {
+		var result error
+		err := self.txWait(ctx, tag, request, DefaultVendTimeout, func(pi money.PollItem) bool {
+			switch pi.HardwareCode {
+			case StatusRevalueApproved:
+				return true
+			case StatusRevalueDenied:
+				result = ErrRevalueDenied
+				return true
+			}
+			return false
+		})
+		if err != nil {
+			return err
+		}
+		return errors.Annotatef(result, "%s amount=%s", tag, amount.FormatCtx(ctx))
+	}}
+}
+
+func (self *CashlessReader) CommandSetup() error {
+	const tag = deviceName + ".setup"
+	const expectLength = 8
+	// VMC feature level 1, no display
+	request := self.request(cmdSetup, 0x00, 0x01, 0x00, 0x00, 0x00)
+	if err := self.Device.TxKnown(request, &self.Device.SetupResponse); err != nil {
+		return errors.Annotate(err, tag)
+	}
+	bs := self.Device.SetupResponse.Bytes()
+	if len(bs) < expectLength || bs[0] != StatusReaderConfig {
+		return errors.Errorf("%s response=%s expected %d bytes", tag, self.Device.SetupResponse.Format(), expectLength)
+	}
+	self.featureLevel = bs[1]
+	self.countryCode = self.Device.ByteOrder.Uint16(bs[2:4])
+	self.scaleFactor = bs[4]
+	self.decimalPlaces = bs[5]
+	if bs[6] != 0 {
+		self.responseTimeout = time.Duration(bs[6]) * time.Second
+	}
+	self.Log.Debugf("%s Reader Feature Level: %d", tag, self.featureLevel)
+	self.Log.Debugf("%s Country / Currency Code: %04x", tag, self.countryCode)
+	self.Log.Debugf("%s Scale Factor: %d Decimal Places: %d", tag, self.scaleFactor, self.decimalPlaces)
+	self.Log.Debugf("%s Maximum Response Time: %v", tag, self.responseTimeout)
+	self.Log.Debugf("%s Miscellaneous Options: %08b", tag, bs[7])
+	return nil
+}
+
+// CommandSetupPrices sends unknown price range, reader must not limit items.
+func (self *CashlessReader) CommandSetupPrices() error {
+	const tag = deviceName + ".setup-prices"
+	request := self.request(cmdSetup, 0x01, 0xff, 0xff, 0x00, 0x00)
+	return errors.Annotate(self.Device.TxKnown(request, nil), tag)
+}
+
+func (self *CashlessReader) newIniter() engine.Doer {
+	const tag = deviceName + ".init"
+	return engine.NewSeq(tag).
+		Append(self.Device.DoReset).
+		Append(engine.Func{Name: tag + "/poll", F: func(ctx context.Context) error {
+			self.Run(ctx, nil, func(money.PollItem) bool { return false })
+			// POLL until it settles on empty response
+			return nil
+		}}).
+		Append(engine.Func0{Name: tag + "/setup", F: self.CommandSetup}).
+		Append(engine.Func0{Name: tag + "/prices", F: self.CommandSetupPrices}).
+		Append(engine.Sleep{Duration: self.Device.DelayNext})
+}
+
+func (self *CashlessReader) request(cmd byte, data ...byte) mdb.Packet {
+	bs := make([]byte, 0, 1+len(data))
+	bs = append(append(bs, self.Device.Address+cmd), data...)
+	return mdb.MustPacketFromBytes(bs, true)
+}
+
+// txWait sends request and polls until `fun` returns true, reader may reply with result right away.
+func (self *CashlessReader) txWait(ctx context.Context, tag string, request mdb.Packet, timeout time.Duration, fun func(money.PollItem) bool) error {
+	self.pollmu.Lock()
+	defer self.pollmu.Unlock()
+
+	response := mdb.Packet{}
+	if err := self.Device.TxKnown(request, &response); err != nil {
+		return errors.Annotate(err, tag)
+	}
+	if self.parse(response.Bytes(), fun) {
+		return nil
+	}
+	d := self.Device.NewPollLoop(tag, self.Device.PacketPoll, timeout, func(p mdb.Packet) (bool, error) {
+		return self.parse(p.Bytes(), fun), nil
+	})
+	return engine.GetGlobal(ctx).Exec(ctx, d)
+}
+
+// parse calls `fun` for each item in response until it returns true.
+func (self *CashlessReader) parse(bs []byte, fun func(money.PollItem) bool) bool {
+	const tag = deviceName + ".poll"
+	for len(bs) != 0 {
+		pi, n := self.parsePollItem(bs)
+		bs = bs[n:]
+		switch pi.Status {
+		case money.StatusError, money.StatusFatal:
+			self.Device.TeleError(errors.Annotate(pi.Error, tag))
+			continue
+		case money.StatusInfo:
+			self.Log.Debugf("%s/info: %s", tag, pi.String())
+		}
+		if fun(pi) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *CashlessReader) parsePollItem(bs []byte) (money.PollItem, int) {
+	const tag = deviceName + ".poll-parse"
+	b := bs[0]
+	withAmount := func(status money.PollItemStatus, length int) (money.PollItem, int) {
+		if len(bs) < 3 {
+			err := errors.Errorf("%s response=%x too short", tag, bs)
+			return money.PollItem{HardwareCode: b, Status: money.StatusError, Error: err}, len(bs)
+		}
+		mdbAmount := self.Device.ByteOrder.Uint16(bs[1:3])
+		nominal := currency.Nominal(self.toAmount(mdbAmount))
+		if b == StatusBeginSession && mdbAmount == 0xffff {
+			nominal = FundsUnknown
+		}
+		return money.PollItem{HardwareCode: b, Status: status, DataNominal: nominal, DataCount: 1}, clip(length, len(bs))
+	}
+
+	switch b {
+	case StatusJustReset:
+		self.setEnabled(false)
+		return money.PollItem{HardwareCode: b, Status: money.StatusWasReset}, 1
+	case StatusReaderConfig:
+		return money.PollItem{HardwareCode: b, Status: money.StatusInfo}, clip(8, len(bs))
+	case StatusDisplayRequest, StatusPeripheralID: // length depends on reader, consume rest
+		return money.PollItem{HardwareCode: b, Status: money.StatusInfo}, len(bs)
+	case StatusBeginSession:
+		length := 3
+		if self.featureLevel >= 2 { // payment media ID, type and data follow
+			length = 10
+		}
+		return withAmount(money.StatusCredit, length)
+	case StatusSessionCancelRequest:
+		return money.PollItem{HardwareCode: b, Status: money.StatusReturnRequest}, 1
+	case StatusVendApproved:
+		return withAmount(money.StatusInfo, 3)
+	case StatusVendDenied, StatusRevalueDenied:
+		return money.PollItem{HardwareCode: b, Status: money.StatusRejected}, 1
+	case StatusEndSession:
+		return money.PollItem{HardwareCode: b, Status: money.StatusDisabled}, 1
+	case StatusCancelled, StatusRevalueApproved, StatusTimeDateRequest:
+		return money.PollItem{HardwareCode: b, Status: money.StatusInfo}, 1
+	case StatusRevalueLimit:
+		return withAmount(money.StatusInfo, 3)
+	case StatusMalfunction:
+		err := errors.Errorf("malfunction response=%x", bs[:clip(2, len(bs))])
+		return money.PollItem{HardwareCode: b, Status: money.StatusError, Error: err}, clip(2, len(bs))
+	case StatusOutOfSequence:
+		length := 1
+		if self.featureLevel >= 2 { // reader state follows
+			length = 2
+		}
+		return money.PollItem{HardwareCode: b, Status: money.StatusError, Error: ErrOutOfSequence}, clip(length, len(bs))
+	}
+
+	err := errors.Errorf("%s CRITICAL cashless unknown response=%x", tag, bs)
+	self.Log.Errorf(err.Error())
+	return money.PollItem{HardwareCode: b, Status: money.StatusFatal, Error: err}, len(bs)
+}
+
+// toAmount converts reader value to currency, includes all scaling factors.
+func (self *CashlessReader) toAmount(u uint16) currency.Amount {
+	x := uint64(u) * uint64(self.scale())
+	for i := self.decimalPlaces; i > 0; i-- {
+		x /= 10
+	}
+	return currency.Amount(x)
+}
+
+func (self *CashlessReader) fromAmount(a currency.Amount) uint16 {
+	x := uint64(a)
+	for i := self.decimalPlaces; i > 0; i-- {
+		x *= 10
+	}
+	x /= uint64(self.scale())
+	if x > 0xffff {
+		x = 0xffff
+	}
+	return uint16(x)
+}
+
+func (self *CashlessReader) scale() uint32 {
+	sf := uint32(self.scaleFactor)
+	if sf == 0 {
+		sf = 1
+	}
+	return sf * self.configScaling
+}
+
+func clip(n, max int) int {
+	if n > max {
+		return max
+	}
+	return n
+}
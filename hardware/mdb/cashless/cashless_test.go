@@ -0,0 +1,153 @@
+package cashless
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/hardware/mdb"
+	"github.com/temoto/vender/hardware/money"
+	"github.com/temoto/vender/internal/engine"
+	state_new "github.com/temoto/vender/internal/state/new"
+)
+
+type _PI = money.PollItem
+
+const testConfig = `hardware { device "cashless" { required=true } } money { scale=100 }`
+
+func mockInitRs() []mdb.MockR {
+	return []mdb.MockR{
+		// initer, RESET
+		{"10", ""},
+		// initer, POLL
+		{"12", "00"},
+		// initer, SETUP config data: level 1, country 1643, scale 1, 2 decimal places, 5 sec
+		{"110001000000", "0101164301020500"},
+		// initer, SETUP max/min prices
+		{"1101ffff0000", ""},
+	}
+}
+
+func testMake(t testing.TB, rs []mdb.MockR) (context.Context, *CashlessReader) {
+	ctx, g := state_new.NewTestContext(t, "", testConfig)
+
+	mock := mdb.MockFromContext(ctx)
+	go func() {
+		mock.Expect(mockInitRs())
+		mock.Expect(rs)
+	}()
+
+	err := Enum(ctx)
+	require.NoError(t, err)
+	dev, err := g.GetDevice(deviceName)
+	require.NoError(t, err)
+
+	return ctx, dev.(*CashlessReader)
+}
+
+func TestCashlessPoll(t *testing.T) {
+	t.Parallel()
+
+	type Case struct {
+		name   string
+		input  string
+		expect []_PI
+	}
+	cases := []Case{
+		{"empty", "", []_PI{}},
+		{"just-reset", "00", []_PI{{HardwareCode: StatusJustReset, Status: money.StatusWasReset}}},
+		{"begin-session", "030190", []_PI{{HardwareCode: StatusBeginSession, Status: money.StatusCredit, DataNominal: 400, DataCount: 1}}},
+		{"begin-session-unknown", "03ffff", []_PI{{HardwareCode: StatusBeginSession, Status: money.StatusCredit, DataNominal: FundsUnknown, DataCount: 1}}},
+		{"cancel-request", "04", []_PI{{HardwareCode: StatusSessionCancelRequest, Status: money.StatusReturnRequest}}},
+		{"approved-end", "05009607", []_PI{
+			{HardwareCode: StatusVendApproved, Status: money.StatusInfo, DataNominal: 150, DataCount: 1},
+			{HardwareCode: StatusEndSession, Status: money.StatusDisabled},
+		}},
+		{"malfunction-skip", "0a1006", []_PI{{HardwareCode: StatusVendDenied, Status: money.StatusRejected}}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx, dev := testMake(t, []mdb.MockR{{"12", c.input}})
+			defer mdb.MockFromContext(ctx).Close()
+
+			pis := make([]_PI, 0)
+			dev.Run(ctx, nil, func(pi money.PollItem) bool {
+				pis = append(pis, pi)
+				return false
+			})
+			assert.Equal(t, c.expect, pis)
+		})
+	}
+}
+
+func TestCashlessVend(t *testing.T) {
+	t.Parallel()
+
+	ctx, dev := testMake(t, nil)
+	mock := mdb.MockFromContext(ctx)
+	defer mock.Close()
+	g := engine.GetGlobal(ctx)
+
+	go mock.Expect([]mdb.MockR{
+		{"1401", ""},
+		{"12", "030190"},
+		{"130000960003", ""},
+		{"12", ""},
+		{"12", "050096"},
+		{"13020003", ""},
+		{"1304", "07"},
+	})
+	require.NoError(t, g.Exec(ctx, dev.AcceptMax(400)))
+	require.True(t, dev.Enabled())
+	require.NoError(t, g.Exec(ctx, dev.AcceptMax(200)), "already enabled")
+	var funds currency.Nominal
+	dev.Run(ctx, nil, func(pi money.PollItem) bool {
+		funds = pi.DataNominal
+		return false
+	})
+	assert.Equal(t, currency.Nominal(400), funds)
+	approved, err := dev.VendRequest(ctx, 150, 3)
+	require.NoError(t, err)
+	assert.Equal(t, currency.Amount(150), approved)
+	require.NoError(t, g.Exec(ctx, dev.VendSuccess(3)))
+	require.NoError(t, g.Exec(ctx, dev.SessionComplete()))
+}
+
+func TestCashlessVendEndSession(t *testing.T) {
+	t.Parallel()
+
+	ctx, dev := testMake(t, []mdb.MockR{
+		{"130000960003", ""},
+		{"12", "07"},
+	})
+	defer mdb.MockFromContext(ctx).Close()
+
+	_, err := dev.VendRequest(ctx, 150, 3)
+	assert.Equal(t, ErrVendDenied, err)
+}
+
+func TestCashlessVendDenied(t *testing.T) {
+	t.Parallel()
+
+	ctx, dev := testMake(t, []mdb.MockR{
+		{"1300012cffff", "06"},
+		{"1303", ""},
+		{"15000064", ""},
+		{"12", "0e"},
+		{"1304", ""},
+		{"12", "07"},
+	})
+	defer mdb.MockFromContext(ctx).Close()
+	g := engine.GetGlobal(ctx)
+
+	_, err := dev.VendRequest(ctx, 300, ItemUnknown)
+	assert.Equal(t, ErrVendDenied, err)
+	require.NoError(t, g.Exec(ctx, dev.VendFailure()))
+	err = g.Exec(ctx, dev.Revalue(100))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrRevalueDenied.Error())
+	require.NoError(t, g.Exec(ctx, dev.SessionComplete()))
+}
@@ -6,6 +6,10 @@ type Config struct { //nolint:maligned
 	Bill struct {
 		ScalingFactor int `hcl:"scaling_factor"`
 	}
+	Cashless struct {
+		Number        int `hcl:"number"` // 1 (default) or 2 for Cashless #2 address
+		ScalingFactor int `hcl:"scaling_factor"`
+	}
 	Coin struct { //nolint:maligned
 		DispenseTimeoutSec int  `hcl:"dispense_timeout_sec"`
 		GiveSmart          bool `hcl:"give_smart"`
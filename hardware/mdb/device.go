@@ -174,11 +174,18 @@ func (self *Device) SetErrorCode(c int32) {
 	}
 }
 
-func (self *Device) State() DeviceState       { return DeviceState(atomic.LoadUint32(&self.state)) }
-func (self *Device) Ready() bool              { return self.State() == DeviceReady }
-func (self *Device) SetState(new DeviceState) { atomic.StoreUint32(&self.state, uint32(new)) }
-func (self *Device) SetReady()                { self.SetState(DeviceReady) }
-func (self *Device) SetOnline()               { self.SetState(DeviceOnline) }
+func (self *Device) State() DeviceState { return DeviceState(atomic.LoadUint32(&self.state)) }
+func (self *Device) Ready() bool        { return self.State() == DeviceReady }
+func (self *Device) SetReady()          { self.SetState(DeviceReady) }
+func (self *Device) SetOnline()         { self.SetState(DeviceOnline) }
+
+// SetState calls Bus.StateChange if state is different.
+func (self *Device) SetState(new DeviceState) {
+	old := DeviceState(atomic.SwapUint32(&self.state, uint32(new)))
+	if old != new && self.bus != nil && self.bus.StateChange != nil {
+		self.bus.StateChange(self, old, new)
+	}
+}
 
 func (self *Device) Reset() error {
 	self.cmdLk.Lock()
@@ -221,6 +228,7 @@ func (self *Device) Keepalive(interval time.Duration, stopch <-chan struct{}) {
 type PollFunc func() (stop bool, err error)
 
 // Call `fun` until `timeout` or it returns stop=true or error.
+// Cancelled ctx interrupts delay between calls.
 func (self *Device) NewFunLoop(tag string, fun PollFunc, timeout time.Duration) engine.Doer {
 	tag += "/poll-loop"
 	return engine.Func{Name: tag, F: func(ctx context.Context) error {
@@ -241,7 +249,11 @@ func (self *Device) NewFunLoop(tag string, fun PollFunc, timeout time.Duration)
 			if timeout == 0 {
 				return errors.Errorf("tag=%s timeout=0 invalid", tag)
 			}
-			time.Sleep(self.DelayNext)
+			select {
+			case <-time.After(self.DelayNext):
+			case <-ctx.Done():
+				return errors.Annotate(ctx.Err(), tag)
+			}
 			if time.Since(tbegin) > timeout {
 				err = errors.Timeoutf(tag)
 				self.SetError(err)
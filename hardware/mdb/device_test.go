@@ -1,9 +1,12 @@
 package mdb_test
 
 import (
+	"context"
 	"encoding/binary"
 	"testing"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/temoto/vender/hardware/mdb"
@@ -31,3 +34,21 @@ func TestDeviceTx(t *testing.T) {
 	require.Error(t, mdb.ErrTimeout, d.TxKnown(d.PacketPoll, nil))
 	assert.Equal(t, mdb.DeviceOffline, d.State())
 }
+
+func TestFunLoopCancel(t *testing.T) {
+	t.Parallel()
+
+	mdbus, mock := mdb.NewMockBus(t)
+	defer mock.Close()
+	d := mdb.Device{DelayNext: time.Second}
+	d.Init(mdbus, 0x30, "mockdev", binary.BigEndian)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	tbegin := time.Now()
+	loop := d.NewFunLoop("busy", func() (bool, error) { return false, nil }, 10*time.Second)
+	err := loop.Do(ctx)
+	require.Error(t, err)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+	assert.Less(t, int64(time.Since(tbegin)), int64(500*time.Millisecond))
+}
@@ -3,6 +3,7 @@ package evend
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/juju/errors"
@@ -43,21 +44,41 @@ func (self *DeviceConveyor) init(ctx context.Context) error {
 	self.DoSetSpeed = self.newSetSpeed()
 
 	doCalibrate := engine.Func{Name: self.name + ".calibrate", F: self.calibrate}
+	positionMax := engine.Arg(devConfig.PositionMax)
+	if positionMax <= 0 {
+		positionMax = math.MaxInt16
+	}
 	doMove := engine.FuncArg{
 		Name: self.name + ".move",
-		F: func(ctx context.Context, arg engine.Arg) error {
-			return self.move(ctx, uint16(arg))
+		Params: []engine.Param{
+			{Name: "pos", Unit: "position", Max: positionMax},
+			{Name: "speed", Unit: "speed", Min: 1, Max: math.MaxUint8, Optional: true},
+		},
+		FArgs: func(ctx context.Context, args []engine.Arg) error {
+			if speed := args[1]; speed != 0 {
+				d, _, err := engine.ArgApply(self.DoSetSpeed, speed)
+				if err == nil {
+					err = g.Engine.Exec(ctx, d)
+				}
+				if err != nil {
+					return errors.Annotate(err, self.name+".move")
+				}
+			}
+			return self.move(ctx, uint16(args[0]))
 		}}
 	moveSeq := engine.NewSeq(self.name + ".move(?)").Append(doCalibrate).Append(doMove)
-	g.Engine.Register(moveSeq.String(), self.Generic.WithRestart(moveSeq))
-	g.Engine.Register(self.name+".set_speed(?)", self.DoSetSpeed)
+	g.Engine.Register(moveSeq.String(), self.Generic.WithRestart(moveSeq),
+		engine.Meta{Description: "move conveyor to position, calibrate first if needed", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".set_speed(?)", self.DoSetSpeed,
+		engine.Meta{Description: "set speed of next moves", Device: self.name, Safe: true})
 
 	doShake := engine.FuncArg{
 		Name: self.name + ".shake",
 		F: func(ctx context.Context, arg engine.Arg) error {
 			return self.shake(ctx, uint8(arg))
 		}}
-	g.Engine.RegisterNewSeq(self.name+".shake(?)", doCalibrate, doShake)
+	g.Engine.Register(self.name+".shake(?)", engine.NewSeq(self.name+".shake(?)").Append(doCalibrate).Append(doShake),
+		engine.Meta{Description: "shake conveyor", Device: self.name, Safe: true})
 
 	err := self.Generic.FIXME_initIO(ctx)
 	if keepaliveInterval > 0 {
@@ -147,7 +168,8 @@ func (self *DeviceConveyor) shake(ctx context.Context, arg uint8) error {
 func (self *DeviceConveyor) newSetSpeed() engine.FuncArg {
 	tag := self.name + ".set_speed"
 
-	return engine.FuncArg{Name: tag, F: func(ctx context.Context, arg engine.Arg) error {
+	params := []engine.Param{{Name: "speed", Min: 1, Max: math.MaxUint8}}
+	return engine.FuncArg{Name: tag, Params: params, F: func(ctx context.Context, arg engine.Arg) error {
 		speed := uint8(arg)
 		bs := []byte{self.dev.Address + 5, 0x10, speed}
 		request := mdb.MustPacketFromBytes(bs, true)
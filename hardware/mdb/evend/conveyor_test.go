@@ -44,6 +44,11 @@ func TestConveyor(t *testing.T) {
 
 		{"dd101f", ""},
 
+		{"dd10c8", ""},
+		{"db", ""},
+		{"da011806", ""},
+		{"db", ""},
+
 		// TODO test + handle it too
 		// {"db", ""},
 		// {"da016707", ""},
@@ -57,4 +62,10 @@ func TestConveyor(t *testing.T) {
 	g.Engine.TestDo(t, ctx, "conveyor_move_elevator")
 	g.Engine.TestDo(t, ctx, "evend.conveyor.shake(4)")
 	g.Engine.TestDo(t, ctx, "evend.conveyor.set_speed(31)")
+	g.Engine.TestDo(t, ctx, "evend.conveyor.move(pos=1560,speed=200)")
+
+	for _, bad := range []string{"evend.conveyor.move(1560,300)", "evend.conveyor.move(speed=100)", "evend.conveyor.set_speed(0)"} {
+		d := g.Engine.Resolve(bad)
+		assert.Error(t, d.Validate(), bad)
+	}
 }
@@ -25,10 +25,14 @@ func (self *DeviceCup) init(ctx context.Context) error {
 
 	g := state.GetGlobal(ctx)
 	doDispense := self.Generic.WithRestart(self.NewDispenseProper())
-	g.Engine.Register(self.name+".dispense", doDispense)
-	g.Engine.Register(self.name+".light_on", self.NewLight(true))
-	g.Engine.Register(self.name+".light_off", self.NewLight(false))
-	g.Engine.Register(self.name+".ensure", self.NewEnsure())
+	g.Engine.Register(self.name+".dispense", doDispense,
+		engine.Meta{Description: "drop one cup", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".light_on", self.NewLight(true),
+		engine.Meta{Description: "turn cup area light on", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".light_off", self.NewLight(false),
+		engine.Meta{Description: "turn cup area light off", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".ensure", self.NewEnsure(),
+		engine.Meta{Description: "prepare cups for dispense, may take long", Device: self.name, Safe: true})
 
 	err := self.Generic.FIXME_initIO(ctx)
 	return errors.Annotate(err, self.name+".init")
@@ -47,7 +51,11 @@ func (self *DeviceCup) NewDispense() engine.Doer {
 		Append(self.Generic.NewAction(tag, 0x01)).
 		Append(engine.Func{Name: tag + "/assert-busy", F: func(ctx context.Context) error {
 			cupConfig := &state.GetGlobal(ctx).Config.Hardware.Evend.Cup
-			time.Sleep(helpers.IntMillisecondDefault(cupConfig.AssertBusyDelayMs, DefaultCupAssertBusyDelay))
+			select {
+			case <-time.After(helpers.IntMillisecondDefault(cupConfig.AssertBusyDelayMs, DefaultCupAssertBusyDelay)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 			response := mdb.Packet{}
 			err := self.dev.TxKnown(self.dev.PacketPoll, &response)
 			if err != nil {
@@ -42,7 +42,8 @@ func (self *DeviceElevator) init(ctx context.Context) error {
 			return nil
 		},
 	}
-	g.Engine.Register(self.name+".move(?)", self.Generic.WithRestart(doMove))
+	g.Engine.Register(self.name+".move(?)", self.Generic.WithRestart(doMove),
+		engine.Meta{Description: "move elevator to position", Device: self.name, Safe: true})
 
 	err := self.Generic.FIXME_initIO(ctx)
 	if keepaliveInterval > 0 {
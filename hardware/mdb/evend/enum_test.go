@@ -34,6 +34,7 @@ device "evend.conveyor" {}
 		{"db", ""}, {"da010000", ""}, {"db", ""}, // conveyor calibrate / conveyor_move(0)
 		{"db", ""}, {"da01fa00", ""}, {"db", ""}, // conveyor move to hopper
 		{"43", ""}, {"420a", ""}, {"43", ""}, // hopper run
+		{"43", ""}, {"420502", ""}, {"43", ""}, // hopper run with pulse mode
 	})
 
 	assert.NoError(t, g.Engine.RegisterParse("hopper1(?)", "evend.conveyor.move(250) evend.hopper1.run(?)"))
@@ -41,4 +42,5 @@ device "evend.conveyor" {}
 	assert.NoError(t, g.Engine.RegisterParse("conveyor_move_elevator", "evend.conveyor.move(1895)"))
 
 	g.Engine.TestDo(t, ctx, "hopper1(10)")
+	g.Engine.TestDo(t, ctx, "evend.hopper1.run(5,mode=2)")
 }
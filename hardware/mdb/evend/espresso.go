@@ -25,11 +25,16 @@ func (self *DeviceEspresso) init(ctx context.Context) error {
 	self.timeout = helpers.IntSecondDefault(espressoConfig.TimeoutSec, DefaultEspressoTimeout)
 	self.Generic.Init(ctx, 0xe8, "espresso", proto2)
 
-	g.Engine.Register(self.name+".grind", self.Generic.WithRestart(self.NewGrind()))
-	g.Engine.Register(self.name+".press", self.NewPress())
-	g.Engine.Register(self.name+".dispose", self.Generic.WithRestart(self.NewRelease()))
-	g.Engine.Register(self.name+".heat_on", self.NewHeat(true))
-	g.Engine.Register(self.name+".heat_off", self.NewHeat(false))
+	g.Engine.Register(self.name+".grind", self.Generic.WithRestart(self.NewGrind()),
+		engine.Meta{Description: "grind coffee portion", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".press", self.NewPress(),
+		engine.Meta{Description: "press ground coffee", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".dispose", self.Generic.WithRestart(self.NewRelease()),
+		engine.Meta{Description: "dispose used coffee", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".heat_on", self.NewHeat(true),
+		engine.Meta{Description: "turn heater on", Device: self.name})
+	g.Engine.Register(self.name+".heat_off", self.NewHeat(false),
+		engine.Meta{Description: "turn heater off", Device: self.name})
 
 	err := self.Generic.FIXME_initIO(ctx)
 	return errors.Annotate(err, self.name+".init")
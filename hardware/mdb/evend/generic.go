@@ -87,6 +87,9 @@ func (self *Generic) FIXME_initIO(ctx context.Context) error {
 
 func (self *Generic) Name() string { return self.name }
 
+func (self *Generic) ValidateOnline() error    { return self.dev.ValidateOnline() }
+func (self *Generic) ValidateErrorCode() error { return self.dev.ValidateErrorCode() }
+
 func (self *Generic) NewErrPollProblem(p mdb.Packet) error {
 	return errors.Errorf("%s POLL=%x -> need to ask problem code", self.logPrefix, p.Bytes())
 }
@@ -3,6 +3,7 @@ package evend
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/juju/errors"
@@ -24,7 +25,7 @@ func (self *DeviceHopper) init(ctx context.Context, addr uint8, nameSuffix strin
 	self.Generic.Init(ctx, addr, name, proto2)
 
 	do := newHopperRun(&self.Generic, fmt.Sprintf("%s.run", self.name), nil)
-	g.Engine.Register(fmt.Sprintf("%s.run(?)", self.name), do)
+	g.Engine.Register(fmt.Sprintf("%s.run(?)", self.name), do, hopperRunMeta(self.name))
 
 	err := self.Generic.FIXME_initIO(ctx)
 	return errors.Annotate(err, self.name+".init")
@@ -45,25 +46,38 @@ func (self *DeviceMultiHopper) init(ctx context.Context) error {
 			fmt.Sprintf("%s%d.run", self.name, i),
 			[]byte{i},
 		)
-		g.Engine.Register(fmt.Sprintf("%s%d.run(?)", self.name, i), do)
+		g.Engine.Register(fmt.Sprintf("%s%d.run(?)", self.name, i), do, hopperRunMeta(self.name))
 	}
 
 	err := self.Generic.FIXME_initIO(ctx)
 	return errors.Annotate(err, self.name+".init")
 }
 
+func hopperRunMeta(device string) engine.Meta {
+	return engine.Meta{Description: "run hopper motor to dispense ingredient", Device: device, Safe: true}
+}
+
+// newHopperRun action `run(units,mode=N)`, mode selects motor pulse pattern.
+// Mode 0 (default) is not sent, device uses own setting.
 func newHopperRun(gen *Generic, tag string, argsPrefix []byte) engine.FuncArg {
-	return engine.FuncArg{Name: tag, F: func(ctx context.Context, arg engine.Arg) error {
+	params := []engine.Param{
+		{Name: "units", Unit: "hopper run unit", Max: math.MaxUint8},
+		{Name: "mode", Unit: "pulse mode", Max: math.MaxUint8, Optional: true},
+	}
+	return engine.FuncArg{Name: tag, Params: params, FArgs: func(ctx context.Context, args []engine.Arg) error {
 		g := state.GetGlobal(ctx)
 		hopperConfig := &g.Config.Hardware.Evend.Hopper
-		units := uint8(arg)
+		units, mode := uint8(args[0]), uint8(args[1])
 		runTimeout := helpers.IntMillisecondDefault(hopperConfig.RunTimeoutMs, DefaultHopperRunTimeout)
 
 		if err := g.Engine.Exec(ctx, gen.NewWaitReady(tag)); err != nil {
 			return err
 		}
-		args := append(argsPrefix, units)
-		if err := gen.txAction(args); err != nil {
+		payload := append(append([]byte(nil), argsPrefix...), units)
+		if mode != 0 {
+			payload = append(payload, mode)
+		}
+		if err := gen.txAction(payload); err != nil {
 			return err
 		}
 		return g.Engine.Exec(ctx, gen.NewWaitDone(tag, runTimeout*time.Duration(units)+HopperTimeout))
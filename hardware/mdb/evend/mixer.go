@@ -3,6 +3,7 @@ package evend
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/juju/errors"
@@ -42,17 +43,23 @@ func (self *DeviceMixer) init(ctx context.Context) error {
 	}}
 	moveSeq := engine.NewSeq(self.name + ".move(?)").Append(doCalibrate).Append(doMove)
 	g.Engine.Register(self.name+".shake(?)",
-		engine.FuncArg{Name: self.name + ".shake", F: func(ctx context.Context, arg engine.Arg) error {
-			return g.Engine.Exec(ctx, self.Generic.WithRestart(self.shake(uint8(arg))))
-		}})
-	g.Engine.Register(self.name+".fan_on", self.NewFan(true))
-	g.Engine.Register(self.name+".fan_off", self.NewFan(false))
-	g.Engine.Register(moveSeq.String(), self.Generic.WithRestart(moveSeq))
+		engine.FuncArg{Name: self.name + ".shake", Params: []engine.Param{{Name: "steps", Unit: "100ms", Max: math.MaxUint8}},
+			F: func(ctx context.Context, arg engine.Arg) error {
+				return g.Engine.Exec(ctx, self.Generic.WithRestart(self.shake(uint8(arg))))
+			}},
+		engine.Meta{Description: "shake mixer", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".fan_on", self.NewFan(true),
+		engine.Meta{Description: "turn fan on", Device: self.name, Safe: true})
+	g.Engine.Register(self.name+".fan_off", self.NewFan(false),
+		engine.Meta{Description: "turn fan off", Device: self.name, Safe: true})
+	g.Engine.Register(moveSeq.String(), self.Generic.WithRestart(moveSeq),
+		engine.Meta{Description: "move mixer to position, 0 is bottom, calibrate first if needed", Device: self.name, Safe: true})
 	g.Engine.Register(self.name+".shake_set_speed(?)",
 		engine.FuncArg{Name: "evend.mixer.shake_set_speed", F: func(ctx context.Context, arg engine.Arg) error {
 			self.shakeSpeed = uint8(arg)
 			return nil
-		}})
+		}},
+		engine.Meta{Description: "set speed of next shakes", Device: self.name, Safe: true})
 
 	err := self.Generic.FIXME_initIO(ctx)
 	if keepaliveInterval > 0 {
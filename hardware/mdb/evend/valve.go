@@ -66,37 +66,56 @@ func (self *DeviceValve) init(ctx context.Context) error {
 
 	waterStock, err := g.Inventory.Get("water")
 	if err == nil {
-		g.Engine.Register("add.water_hot(?)", waterStock.Wrap(self.DoPourHot))
-		g.Engine.Register("add.water_cold(?)", waterStock.Wrap(self.DoPourCold))
-		g.Engine.Register("add.water_espresso(?)", waterStock.Wrap(self.DoPourEspresso))
+		g.Engine.Register("add.water_hot(?)", waterStock.Wrap(self.DoPourHot),
+			engine.Meta{Description: "pour hot water, argument in water stock units", Device: self.name, Safe: true})
+		g.Engine.Register("add.water_cold(?)", waterStock.Wrap(self.DoPourCold),
+			engine.Meta{Description: "pour cold water, argument in water stock units", Device: self.name, Safe: true})
+		g.Engine.Register("add.water_espresso(?)", waterStock.Wrap(self.DoPourEspresso),
+			engine.Meta{Description: "pour espresso water, argument in water stock units", Device: self.name, Safe: true})
 		self.cautionPartUnit = uint8(waterStock.TranslateHw(engine.Arg(valveConfig.CautionPartMl)))
 	} else {
 		self.dev.Log.Errorf("invalid config, stock water not found err=%v", err)
 	}
 
-	g.Engine.Register("evend.valve.check_temp_hot", self.doCheckTempHot)
-	g.Engine.Register("evend.valve.get_temp_hot", self.doGetTempHot)
-	g.Engine.Register("evend.valve.set_temp_hot(?)", self.DoSetTempHot)
+	g.Engine.Register("evend.valve.check_temp_hot", self.doCheckTempHot,
+		engine.Meta{Description: "validate hot water temperature", Device: self.name, Safe: true})
+	g.Engine.Register("evend.valve.get_temp_hot", self.doGetTempHot,
+		engine.Meta{Description: "read hot water temperature", Device: self.name, Safe: true})
+	g.Engine.Register("evend.valve.set_temp_hot(?)", self.DoSetTempHot,
+		engine.Meta{Description: "set target hot water temperature", Device: self.name})
 	g.Engine.Register("evend.valve.set_temp_hot_config", engine.Func{F: func(ctx context.Context) error {
-		d, _, err := self.DoSetTempHot.Apply(engine.Arg(valveConfig.TemperatureHot))
+		d, _, err := engine.ArgApply(self.DoSetTempHot, engine.Arg(valveConfig.TemperatureHot))
 		if err != nil {
 			return err
 		}
 		return g.Engine.Exec(ctx, d)
-	}})
-	g.Engine.Register("evend.valve.pour_espresso(?)", self.DoPourEspresso.(engine.Doer))
-	g.Engine.Register("evend.valve.pour_cold(?)", self.DoPourCold.(engine.Doer))
-	g.Engine.Register("evend.valve.pour_hot(?)", self.DoPourHot.(engine.Doer))
-	g.Engine.Register("evend.valve.cold_open", self.NewValveCold(true))
-	g.Engine.Register("evend.valve.cold_close", self.NewValveCold(false))
-	g.Engine.Register("evend.valve.hot_open", self.NewValveHot(true))
-	g.Engine.Register("evend.valve.hot_close", self.NewValveHot(false))
-	g.Engine.Register("evend.valve.boiler_open", self.NewValveBoiler(true))
-	g.Engine.Register("evend.valve.boiler_close", self.NewValveBoiler(false))
-	g.Engine.Register("evend.valve.pump_espresso_start", self.NewPumpEspresso(true))
-	g.Engine.Register("evend.valve.pump_espresso_stop", self.NewPumpEspresso(false))
-	g.Engine.Register("evend.valve.pump_start", self.NewPump(true))
-	g.Engine.Register("evend.valve.pump_stop", self.NewPump(false))
+	}}, engine.Meta{Description: "set target hot water temperature from config", Device: self.name, Safe: true})
+	g.Engine.Register("evend.valve.pour_espresso(?)", self.DoPourEspresso.(engine.Doer),
+		engine.Meta{Description: "pour espresso water in hardware units, without stock accounting", Device: self.name})
+	g.Engine.Register("evend.valve.pour_cold(?)", self.DoPourCold.(engine.Doer),
+		engine.Meta{Description: "pour cold water in hardware units, without stock accounting", Device: self.name})
+	g.Engine.Register("evend.valve.pour_hot(?)", self.DoPourHot.(engine.Doer),
+		engine.Meta{Description: "pour hot water in hardware units, without stock accounting", Device: self.name})
+	g.Engine.Register("evend.valve.cold_open", self.NewValveCold(true),
+		engine.Meta{Description: "open cold water valve", Device: self.name})
+	g.Engine.Register("evend.valve.cold_close", self.NewValveCold(false),
+		engine.Meta{Description: "close cold water valve", Device: self.name})
+	g.Engine.Register("evend.valve.hot_open", self.NewValveHot(true),
+		engine.Meta{Description: "open hot water valve", Device: self.name})
+	g.Engine.Register("evend.valve.hot_close", self.NewValveHot(false),
+		engine.Meta{Description: "close hot water valve", Device: self.name})
+	g.Engine.Register("evend.valve.boiler_open", self.NewValveBoiler(true),
+		engine.Meta{Description: "open boiler valve", Device: self.name})
+	g.Engine.Register("evend.valve.boiler_close", self.NewValveBoiler(false),
+		engine.Meta{Description: "close boiler valve", Device: self.name})
+	g.Engine.Register("evend.valve.pump_espresso_start", self.NewPumpEspresso(true),
+		engine.Meta{Description: "start espresso pump, runs until stop", Device: self.name})
+	g.Engine.Register("evend.valve.pump_espresso_stop", self.NewPumpEspresso(false),
+		engine.Meta{Description: "stop espresso pump", Device: self.name, Safe: true})
+	g.Engine.Register("evend.valve.pump_start", self.NewPump(true),
+		engine.Meta{Description: "start pump, runs until stop", Device: self.name})
+	g.Engine.Register("evend.valve.pump_stop", self.NewPump(false),
+		engine.Meta{Description: "stop pump", Device: self.name, Safe: true})
 
 	err = self.Generic.FIXME_initIO(ctx)
 	return errors.Annotate(err, self.name+".init")
@@ -182,7 +201,7 @@ func (self *DeviceValve) newPourCareful(name string, arg1 byte, abort engine.Doe
 				}
 				d = self.Generic.NewWaitDone(tag, self.pourTimeout)
 				if err := e.Exec(ctx, d); err != nil {
-					_ = e.Exec(ctx, abort) // TODO likely redundant
+					_ = e.Exec(engine.Detach(ctx), abort) // TODO likely redundant
 					return err
 				}
 				units -= self.cautionPartUnit
@@ -192,6 +211,10 @@ func (self *DeviceValve) newPourCareful(name string, arg1 byte, abort engine.Doe
 				return err
 			}
 			err := e.Exec(ctx, self.Generic.NewWaitDone(tag, self.pourTimeout))
+			if err != nil && ctx.Err() != nil {
+				// aborted, don't leave pump running
+				_ = e.Exec(engine.Detach(ctx), abort)
+			}
 			return err
 		}}
 
@@ -31,9 +31,10 @@ type FeatureNotSupported string
 func (self FeatureNotSupported) Error() string { return string(self) }
 
 type Bus struct {
-	Error func(error)
-	Log   *log2.Log
-	u     Uarter
+	Error       func(error)
+	Log         *log2.Log
+	StateChange func(d *Device, old, new DeviceState) // optional, called on every device state change
+	u           Uarter
 }
 
 func NewBus(u Uarter, log *log2.Log, errfun func(error)) *Bus {
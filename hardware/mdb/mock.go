@@ -25,10 +25,11 @@ func (self MockR) String() string {
 }
 
 type MockUart struct {
-	t  testing.TB
-	mu sync.Mutex
-	m  map[string]string
-	q  chan MockR
+	t       testing.TB
+	mu      sync.Mutex
+	m       map[string]string
+	q       chan MockR
+	offline bool
 }
 
 func NewMockUart(t testing.TB) *MockUart {
@@ -65,6 +66,9 @@ func (self *MockUart) Break(d, sleep time.Duration) error {
 }
 
 func (self *MockUart) Tx(request, response []byte) (n int, err error) {
+	if self.offline {
+		return 0, ErrTimeout
+	}
 	self.t.Helper()
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -171,6 +175,15 @@ func NewMockBus(t testing.TB) (*Bus, *MockUart) {
 	return b, mock
 }
 
+// NewMockBusOffline returns bus without devices, every request times out.
+// Used to register drivers without hardware, outside of tests.
+func NewMockBusOffline(log *log2.Log) *Bus {
+	mock := &MockUart{offline: true, q: make(chan MockR)}
+	return NewBus(mock, log, func(e error) {
+		log.Debugf("bus.Error: %v", e)
+	})
+}
+
 const MockContextKey = "test/mdb-mock"
 
 // sorry for this ugly convolution
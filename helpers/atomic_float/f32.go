@@ -15,6 +15,11 @@ func (f *F32) Store(new float32) {
 	atomic.StoreUint32((*uint32)(f), math.Float32bits(new))
 }
 
+// Swap stores new and returns old value.
+func (f *F32) Swap(new float32) float32 {
+	return math.Float32frombits(atomic.SwapUint32((*uint32)(f), math.Float32bits(new)))
+}
+
 func (f *F32) Add(delta float32) float32 {
 tryAgain:
 	oldbits := atomic.LoadUint32((*uint32)(f))
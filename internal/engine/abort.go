@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// WithAbort returns ctx cancelled by Abort. Call release when execution ends.
+// Only the latest execution is abortable, it's the current vend.
+func (self *Engine) WithAbort(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	self.abort.Lock()
+	self.abort.seq++
+	seq := self.abort.seq
+	self.abort.cancel = cancel
+	self.abort.Unlock()
+
+	release := func() {
+		self.abort.Lock()
+		if self.abort.seq == seq {
+			self.abort.cancel = nil
+		}
+		self.abort.Unlock()
+		cancel()
+	}
+	return ctx, release
+}
+
+// Abort cancels execution started with WithAbort, false if there is none.
+// Doers observe ctx.Done(), so running action stops at next poll or delay.
+func (self *Engine) Abort() bool {
+	self.abort.Lock()
+	cancel := self.abort.cancel
+	self.abort.cancel = nil
+	self.abort.Unlock()
+	if cancel == nil {
+		return false
+	}
+	self.Log.Infof("engine abort")
+	cancel()
+	return true
+}
+
+// Detach returns ctx with same values but without parent cancellation and deadline.
+// Used to run cleanup actions after abort.
+func Detach(ctx context.Context) context.Context { return detached{ctx} }
+
+type detached struct{ context.Context }
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
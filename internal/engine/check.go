@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Composite is implemented by actions containing other actions.
+// Checker walks scenario graph through Children without executing it.
+type Composite interface {
+	Children() []Doer
+}
+
+func (seq *Seq) Children() []Doer     { return seq.items }
+func (self *Par) Children() []Doer    { return self.items }
+func (self RepeatN) Children() []Doer { return []Doer{self.D} }
+func (self *RestartError) Children() []Doer {
+	if self.Reset == nil {
+		return []Doer{self.Doer}
+	}
+	return []Doer{self.Doer, self.Reset}
+}
+func (self *If) Children() []Doer {
+	result := []Doer{self.Cond, self.Then}
+	if self.Else != nil {
+		result = append(result, self.Else)
+	}
+	return result
+}
+
+// compile-time interface checks
+var _ Composite = &Seq{}
+var _ Composite = &Par{}
+var _ Composite = RepeatN{}
+var _ Composite = &RestartError{}
+var _ Composite = &If{}
+
+// Checker resolves scenarios statically, for `vender config-check`.
+// Usage: NewChecker, Aliases once, Check for every scenario, then Issues.
+type Checker struct {
+	e       *Engine
+	aliases map[string]struct{}
+	tainted map[string]struct{} // aliases in or depending on cycle, must not be forced
+	seen    map[string]struct{}
+	used    map[string]struct{}
+	issues  []error
+}
+
+func NewChecker(e *Engine) *Checker {
+	return &Checker{
+		e:       e,
+		aliases: make(map[string]struct{}),
+		tainted: make(map[string]struct{}),
+		seen:    make(map[string]struct{}),
+		used:    make(map[string]struct{}),
+	}
+}
+
+// Aliases registers named scenarios and reports reference cycles among them.
+// Resolving alias cycle with arguments recurses forever, so it's done before any Check.
+func (self *Checker) Aliases(names ...string) {
+	for _, name := range names {
+		self.aliases[checkKey(name)] = struct{}{}
+	}
+	refs := make(map[string][]string, len(names))
+	keys := make([]string, 0, len(self.aliases))
+	for key := range self.aliases {
+		keys = append(keys, key)
+		self.e.lk.RLock()
+		d := self.e.actions[key]
+		self.e.lk.RUnlock()
+		if c, ok := d.(Composite); ok {
+			for _, child := range c.Children() {
+				refs[key] = self.aliasRefs(key, child, refs[key])
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make(map[string]int, len(keys))
+	path := make([]string, 0, 8)
+	var visit func(key string)
+	visit = func(key string) {
+		color[key] = grey
+		path = append(path, key)
+		for _, ref := range refs[key] {
+			switch color[ref] {
+			case white:
+				visit(ref)
+			case grey:
+				i := len(path) - 1
+				for path[i] != ref {
+					i--
+				}
+				cycle := append(append([]string{}, path[i:]...), ref)
+				self.issues = append(self.issues, errors.Errorf("alias cycle %s", strings.Join(cycle, " -> ")))
+				for _, c := range cycle {
+					self.tainted[c] = struct{}{}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		color[key] = black
+	}
+	for _, key := range keys {
+		if color[key] == white {
+			visit(key)
+		}
+	}
+	// aliases depending on cycle
+	for changed := true; changed; {
+		changed = false
+		for _, key := range keys {
+			if _, ok := self.tainted[key]; ok {
+				continue
+			}
+			for _, ref := range refs[key] {
+				if _, ok := self.tainted[ref]; ok {
+					self.tainted[key] = struct{}{}
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// Check resolves every action in scenario `d` named `tag`.
+// Parametrized scenario, like alias `name(?)` or `register_add`, may have arguments not applied.
+func (self *Checker) Check(tag string, d Doer, parametrized bool) {
+	if d == nil {
+		return
+	}
+	self.walk(tag, d, parametrized)
+}
+
+func (self *Checker) Issues() []error { return self.issues }
+
+// Used reports if any resolved action name starts with `prefix`.
+func (self *Checker) Used(prefix string) bool {
+	for name := range self.used {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *Checker) walk(tag string, d Doer, parametrized bool) {
+	switch x := d.(type) {
+	case *Lazy:
+		key := checkKey(x.Name)
+		self.used[key] = struct{}{}
+		if _, ok := self.tainted[key]; ok {
+			return // cycle reported by Aliases
+		}
+		seenKey := x.Name
+		if parametrized {
+			seenKey += "/parametrized"
+		}
+		if _, ok := self.seen[seenKey]; ok {
+			return
+		}
+		self.seen[seenKey] = struct{}{}
+		if !parametrized && parseArg(x.Name).arg == "?" {
+			self.lazyIssue(tag, x, errors.Annotatef(ErrArgNotApplied, "action=%s", x.Name))
+			return
+		}
+		resolved, _, err := x.force()
+		if err != nil {
+			self.lazyIssue(tag, x, err)
+			return
+		}
+		self.walk(tag, resolved, parametrized)
+
+	case FuncArg:
+		self.used[x.Name] = struct{}{}
+		if !x.set && !parametrized {
+			self.issues = append(self.issues, errors.Annotatef(ErrArgNotApplied, "scenario=%s action=%s", tag, x.Name))
+		}
+
+	case CondArg:
+		self.used[x.Name] = struct{}{}
+		if !x.set && !parametrized {
+			self.issues = append(self.issues, errors.Annotatef(ErrArgNotApplied, "scenario=%s action=%s", tag, x.Name))
+		}
+
+	case Fail:
+		self.issues = append(self.issues, errors.Annotatef(x.E, "scenario=%s", tag))
+
+	default:
+		self.used[checkKey(d.String())] = struct{}{}
+		if c, ok := d.(Composite); ok {
+			for _, child := range c.Children() {
+				self.walk(tag, child, parametrized)
+			}
+		}
+	}
+}
+
+// aliasRefs lists aliases referenced by `d` in alias `node` without forcing anything.
+// lazyIssue reports problem with action location when known.
+func (self *Checker) lazyIssue(tag string, l *Lazy, err error) {
+	if l.Pos.Line != 0 {
+		err = &ParseError{Pos: l.Pos, Tag: tag, Word: l.Name, Err: err}
+	} else {
+		err = errors.Annotatef(err, "scenario=%s", tag)
+	}
+	self.issues = append(self.issues, err)
+}
+
+func (self *Checker) aliasRefs(node string, d Doer, acc []string) []string {
+	var key string
+	switch x := d.(type) {
+	case *Lazy:
+		key = checkKey(x.Name)
+	case *Seq:
+		// node's own scenario, wrapped by Compensate
+		if key = checkKey(x.name); key == node {
+			key = ""
+		}
+	}
+	if key != "" {
+		if _, ok := self.aliases[key]; ok {
+			return append(acc, key)
+		}
+	}
+	if c, ok := d.(Composite); ok {
+		for _, child := range c.Children() {
+			acc = self.aliasRefs(node, child, acc)
+		}
+	}
+	return acc
+}
+
+// checkKey normalizes action name with arguments, `foo(1,2)` -> `foo(?)`.
+func checkKey(name string) string {
+	if tok := parseArg(name); tok.ok {
+		return tok.norm
+	}
+	return name
+}
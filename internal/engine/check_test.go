@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	_, e := newTestContext(t)
+	e.Register("move(?)", FuncArg{Name: "move", F: func(context.Context, Arg) error { return nil }})
+	e.Register("stock.cup.spend1", Nothing{Name: "stock.cup.spend1"})
+	aliases := map[string]string{
+		"serve(?)":  "move(?) stock.cup.spend1",
+		"loop_a":    "loop_b(1)",
+		"loop_b(?)": "move(?) loop_a",
+		"no_arg":    "move(?)",
+	}
+	for name, scenario := range aliases {
+		require.NoError(t, e.RegisterParse(name, scenario))
+	}
+
+	c := NewChecker(e)
+	c.Aliases("serve(?)", "loop_a", "loop_b(?)", "no_arg")
+	for _, name := range []string{"serve(?)", "loop_a", "loop_b(?)", "no_arg"} {
+		c.Check(name, e.Resolve(name), strings.HasSuffix(name, "(?)"))
+	}
+	d, err := e.ParseText("menu", "serve(5) typo if(cond) loop_a end")
+	require.NoError(t, err)
+	c.Check("menu", d, false)
+
+	ss := make([]string, 0)
+	for _, err := range c.Issues() {
+		ss = append(ss, err.Error())
+	}
+	all := strings.Join(ss, "\n")
+	t.Log(all)
+	assert.Equal(t, 4, len(ss))
+	assert.Contains(t, all, "alias cycle loop_a -> loop_b(?) -> loop_a")
+	assert.Contains(t, all, "scenario=no_arg action=move: Argument is not applied")
+	assert.Contains(t, all, "1:10 scenario=menu word=typo: action=typo not resolved")
+	assert.Contains(t, all, "1:15 scenario=menu word=cond: action=cond not resolved")
+	assert.Contains(t, d.Validate().Error(), ": 1:10\n")
+	assert.True(t, c.Used("stock.cup."))
+	assert.False(t, c.Used("stock.milk."))
+}
@@ -8,13 +8,16 @@ import (
 )
 
 type Config struct {
-	Aliases        []Alias  `hcl:"alias"`
-	OnBoot         []string `hcl:"on_boot"`
-	OnMenuError    []string `hcl:"on_menu_error"`
-	OnServiceBegin []string `hcl:"on_service_begin"`
-	OnServiceEnd   []string `hcl:"on_service_end"`
-	OnFrontBegin   []string `hcl:"on_front_begin"`
-	OnBroken       []string `hcl:"on_broken"`
+	Aliases        []Alias        `hcl:"alias"`
+	OnBoot         []string       `hcl:"on_boot"`
+	OnMenuError    []string       `hcl:"on_menu_error"`
+	OnServiceBegin []string       `hcl:"on_service_begin"`
+	OnServiceEnd   []string       `hcl:"on_service_end"`
+	OnFrontBegin   []string       `hcl:"on_front_begin"`
+	OnBroken       []string       `hcl:"on_broken"`
+	Vars           map[string]int `hcl:"vars"` // scenario variables `${name}` default values
+	Jobs           []*Job         `hcl:"job"`
+	Hooks          []*Hook        `hcl:"hook"`
 	Inventory      Inventory
 	Menu           struct {
 		Items []*MenuItem `hcl:"item"`
@@ -24,41 +27,91 @@ type Config struct {
 		MinUs     int    `hcl:"min_us"`
 		LogFormat string `hcl:"log_format"`
 	}
+	Simulate struct {
+		Models []SimModel `hcl:"model"`
+	}
+	TraceBuffer int `hcl:"trace_buffer"` // recent Exec traces kept for debug, 0 traces only menu items
 }
 
 type Alias struct {
 	Name     string `hcl:"name,key"`
 	Scenario string `hcl:"scenario"`
+	Undo     string `hcl:"undo"` // compensation, runs if vend fails after this step
+
+	ScenarioPos engine.Pos  `hcl:"-"`
+	UndoPos     engine.Pos  `hcl:"-"`
+	Doer        engine.Doer `hcl:"-"`
+}
+
+// Job is periodic scenario, runs every `Every` aligned to `At` time of day.
+type Job struct {
+	Name     string `hcl:"name,key"`
+	Every    string `hcl:"every"` // Go duration, default 24h
+	At       string `hcl:"at"`    // local time HH:MM, empty = start + every
+	Scenario string `hcl:"scenario"`
+	When     string `hcl:"when"` // idle (default) waits until UI is idle, any does not
+
+	ScenarioPos engine.Pos  `hcl:"-"`
+	Doer        engine.Doer `hcl:"-"`
+}
+
+// Hook runs scenario on engine event, see engine.Event.
+// Event is name or `name:subject`, payload is in variables `${value}`.
+type Hook struct {
+	Event    string `hcl:"event,key"`
+	Scenario string `hcl:"scenario"`
 
-	Doer engine.Doer `hcl:"-"`
+	ScenarioPos engine.Pos  `hcl:"-"`
+	Doer        engine.Doer `hcl:"-"`
+}
+
+// SimModel replaces actions matching regexp in `vender simulate`, see engine.Simulation.
+type SimModel struct {
+	Match    string `hcl:"match,key"`
+	Duration string `hcl:"duration"`
+	PerArg   string `hcl:"per_arg"` // added for each unit of first argument
+	Result   bool   `hcl:"result"`  // condition test result
 }
 
 type MenuItem struct {
-	Code      string `hcl:"code,key"`
-	Name      string `hcl:"name"`
-	XXX_Price int    `hcl:"price"` // use scaled `Price`, this is for decoding config only
-	Scenario  string `hcl:"scenario"`
+	Code      string         `hcl:"code,key"`
+	Name      string         `hcl:"name"`
+	XXX_Price int            `hcl:"price"` // use scaled `Price`, this is for decoding config only
+	Scenario  string         `hcl:"scenario"`
+	Vars      map[string]int `hcl:"vars"` // override engine.vars in this item scenario
 
-	Price currency.Amount `hcl:"-"`
-	Doer  engine.Doer     `hcl:"-"`
+	Price       currency.Amount `hcl:"-"`
+	ScenarioPos engine.Pos      `hcl:"-"` // location in config file for parse errors
+	Doer        engine.Doer     `hcl:"-"`
 }
 
 func (self *MenuItem) String() string { return fmt.Sprintf("menu.%s %s", self.Code, self.Name) }
 
 type Inventory struct { //nolint:maligned
-	Persist     bool    `hcl:"persist"`
-	Stocks      []Stock `hcl:"stock"`
-	TeleAddName bool    `hcl:"tele_add_name"` // send stock names to telemetry; false to save network usage
+	Persist     bool           `hcl:"persist"`
+	Stocks      []Stock        `hcl:"stock"`
+	TeleAddName bool           `hcl:"tele_add_name"` // send stock names to telemetry; false to save network usage
+	Migrate     []StockMigrate `hcl:"migrate"`
+}
+
+// StockMigrate maps persisted stock to configured one at boot, rules with same `to` merge values.
+type StockMigrate struct {
+	From string `hcl:"from,key"`
+	To   string `hcl:"to"`
 }
 
 type Stock struct { //nolint:maligned
-	Name        string  `hcl:"name,key"`
-	Code        int     `hcl:"code"`
-	Check       bool    `hcl:"check"`
-	Min         float32 `hcl:"min"`
-	HwRate      float32 `hcl:"hw_rate"`
-	SpendRate   float32 `hcl:"spend_rate"`
-	RegisterAdd string  `hcl:"register_add"`
+	Name        string      `hcl:"name,key"`
+	Code        int         `hcl:"code"`
+	Check       bool        `hcl:"check"`
+	Min         float32     `hcl:"min"`
+	Capacity    float32     `hcl:"capacity"` // full stock value, for fill percent and refill to full
+	HwRate      float32     `hcl:"hw_rate"`
+	HwTable     [][]float32 `hcl:"hw_table"` // [[stock, hw]...] interpolated, overrides hw_rate
+	SpendRate   float32     `hcl:"spend_rate"`
+	RegisterAdd string      `hcl:"register_add"`
+	ShelfLife   string      `hcl:"shelf_life"`  // refill is a lot expiring after duration, "72h"
+	ExpireWarn  string      `hcl:"expire_warn"` // telemetry reports expiry within duration, default 24h
 }
 
 func (self *Stock) String() string {
@@ -45,9 +45,18 @@ func (self Func0) String() string               { return self.Name }
 
 type Sleep struct{ time.Duration }
 
-func (self Sleep) Validate() error              { return nil }
-func (self Sleep) Do(ctx context.Context) error { time.Sleep(self.Duration); return nil }
-func (self Sleep) String() string               { return fmt.Sprintf("Sleep(%v)", self.Duration) }
+func (self Sleep) Validate() error { return nil }
+func (self Sleep) Do(ctx context.Context) error {
+	t := time.NewTimer(self.Duration)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+func (self Sleep) String() string { return fmt.Sprintf("Sleep(%v)", self.Duration) }
 
 type RepeatN struct {
 	N uint
@@ -60,6 +69,9 @@ func (self RepeatN) Do(ctx context.Context) error {
 	log := log2.ContextValueLogger(ctx)
 	var err error
 	for i := uint(1); i <= self.N && err == nil; i++ {
+		if err = ctx.Err(); err != nil {
+			break
+		}
 		log.Debugf("engine loop %d/%d", i, self.N)
 		err = GetGlobal(ctx).ExecPart(ctx, self.D)
 	}
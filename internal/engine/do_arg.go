@@ -3,6 +3,8 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
 )
@@ -13,29 +15,157 @@ var ErrArgOverwrite = errors.Errorf("Argument already applied")
 type MaybeBool uint8
 
 func ArgApply(d Doer, arg Arg) (Doer, bool, error) {
-	// log.Printf("ArgApply d=%s arg=%v", d.String(), arg)
+	return ArgsApply(d, Args{Pos: []Arg{arg}})
+}
+
+func ArgsApply(d Doer, args Args) (Doer, bool, error) {
+	// log.Printf("ArgsApply d=%s args=%v", d.String(), args)
 	var err error
 	d, _, err = Force(d)
 	if err != nil {
 		return nil, false, err
 	}
 	if aa, ok := d.(ArgApplier); ok {
-		return aa.Apply(arg)
+		return aa.Apply(args)
 	}
 	return d, false, nil
 }
 
 type Arg int32 // maybe interface{}
 type ArgApplier interface {
-	Apply(a Arg) (Doer, bool, error)
+	Apply(Args) (Doer, bool, error)
+}
+
+// Args is positional and named arguments of one action, `foo(1210,speed=300)`.
+type Args struct {
+	Pos   []Arg
+	Named []NamedArg
+}
+
+type NamedArg struct {
+	Name  string
+	Value Arg
+}
+
+func (a Args) Len() int { return len(a.Pos) + len(a.Named) }
+func (a Args) String() string {
+	ss := make([]string, 0, a.Len())
+	for _, v := range a.Pos {
+		ss = append(ss, strconv.Itoa(int(v)))
+	}
+	for _, na := range a.Named {
+		ss = append(ss, fmt.Sprintf("%s=%d", na.Name, na.Value))
+	}
+	return strings.Join(ss, ",")
+}
+
+// ParseArgs accepts text between parens, `1210,speed=300`.
+// Values may be integer expressions `12*10/4`, see EvalExpr.
+func ParseArgs(s string) (Args, error) { return parseArgs(s, exprEnv{}) }
+
+func parseArgs(s string, env exprEnv) (Args, error) {
+	a := Args{}
+	for _, item := range strings.Split(s, ",") {
+		name, value := "", item
+		if i := strings.IndexByte(item, '='); i != -1 {
+			name, value = strings.TrimSpace(item[:i]), item[i+1:]
+			if name == "" {
+				return Args{}, errors.NotValidf("argument=%s name", item)
+			}
+		}
+		n, err := evalExpr(value, env)
+		if err != nil {
+			return Args{}, errors.Annotatef(err, "argument=%s value", item)
+		}
+		if name == "" {
+			if len(a.Named) != 0 {
+				return Args{}, errors.NotValidf("argument=%s positional after named", item)
+			}
+			a.Pos = append(a.Pos, n)
+		} else {
+			a.Named = append(a.Named, NamedArg{Name: name, Value: n})
+		}
+	}
+	return a, nil
+}
+
+// Param describes one action argument for arity and range validation.
+// Unit is informational, shown by Engine.Describe.
+// Min,Max=0,0 means any value. Optional argument takes Default when omitted.
+type Param struct {
+	Name     string `json:"name,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Min      Arg    `json:"min,omitempty"`
+	Max      Arg    `json:"max,omitempty"`
+	Default  Arg    `json:"default,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+func (p Param) String() string {
+	if p.Name == "" {
+		return "arg"
+	}
+	return p.Name
+}
+
+var defaultParams = []Param{{}}
+
+// BindArgs matches positional and named `args` to `params`.
+// Result has one value per param in params order.
+func BindArgs(params []Param, args Args) ([]Arg, error) {
+	if len(args.Pos) > len(params) {
+		return nil, errors.NotValidf("arguments count=%d max=%d", len(args.Pos), len(params))
+	}
+	result := make([]Arg, len(params))
+	given := make([]bool, len(params))
+	for i, v := range args.Pos {
+		result[i], given[i] = v, true
+	}
+	for _, na := range args.Named {
+		i := findParam(params, na.Name)
+		if i == -1 {
+			return nil, errors.NotValidf("argument=%s unknown", na.Name)
+		}
+		if given[i] {
+			return nil, errors.NotValidf("argument=%s duplicate", na.Name)
+		}
+		result[i], given[i] = na.Value, true
+	}
+	for i, p := range params {
+		if !given[i] {
+			if !p.Optional {
+				return nil, errors.NotValidf("argument=%s missing", p.String())
+			}
+			result[i] = p.Default
+			continue
+		}
+		if p.Min < p.Max && (result[i] < p.Min || result[i] > p.Max) {
+			return nil, errors.NotValidf("argument=%s value=%d out of range %d..%d", p.String(), result[i], p.Min, p.Max)
+		}
+	}
+	return result, nil
 }
 
+func findParam(params []Param, name string) int {
+	for i, p := range params {
+		if p.Name != "" && p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FuncArg is action with arguments, described by Params, default is one required argument.
+// F receives first argument, FArgs if set receives all in Params order.
+// Action with empty non-nil Params must use FArgs.
 type FuncArg struct {
-	Name string
-	F    func(context.Context, Arg) error
-	V    ValidateFunc
-	arg  Arg
-	set  bool
+	Name   string
+	F      func(context.Context, Arg) error
+	FArgs  func(context.Context, []Arg) error
+	V      ValidateFunc
+	Params []Param
+	args   []Arg
+	set    bool
 }
 
 func (fa FuncArg) Validate() error {
@@ -48,65 +178,99 @@ func (fa FuncArg) Do(ctx context.Context) error {
 	if !fa.set {
 		return errors.Annotatef(ErrArgNotApplied, FmtErrContext, fa.Name)
 	}
-	return fa.F(ctx, fa.arg)
+	if fa.FArgs != nil {
+		return fa.FArgs(ctx, fa.args)
+	}
+	if len(fa.args) == 0 {
+		return fa.errNoFArgs()
+	}
+	return fa.F(ctx, fa.args[0])
 }
 func (fa FuncArg) String() string {
 	if !fa.set {
 		return fmt.Sprintf("%s:Arg?", fa.Name)
 	}
-	return fmt.Sprintf("%s:%v", fa.Name, fa.arg)
+	return fmt.Sprintf("%s:%s", fa.Name, Args{Pos: fa.args}.String())
 }
 
-func (fa FuncArg) Apply(a Arg) (Doer, bool, error) {
+func (fa FuncArg) Apply(args Args) (Doer, bool, error) {
 	if fa.set {
 		return nil, false, errors.Annotatef(ErrArgOverwrite, FmtErrContext, fa.Name)
 	}
+	params := fa.Params
+	if params == nil {
+		params = defaultParams
+	}
+	if len(params) == 0 && fa.FArgs == nil {
+		return nil, false, fa.errNoFArgs()
+	}
+	values, err := BindArgs(params, args)
+	if err != nil {
+		return nil, false, errors.Annotatef(err, FmtErrContext, fa.Name)
+	}
 	// Copied already because (fa FuncArg) not pointer receiver
 	// this is redundant line to make copy clear for reading.
 	copied := fa
-	copied.arg = a
+	copied.args = values
 	copied.set = true
 	return copied, true, nil
 }
 
+func (fa FuncArg) errNoFArgs() error {
+	return errors.NotValidf("%s without params and FArgs", fa.Name)
+}
+
 // Apply makes copy of Seq, applying `arg` to exactly one (first) placeholder.
-func (seq *Seq) Apply(arg Arg) (Doer, bool, error) {
+func (seq *Seq) Apply(args Args) (Doer, bool, error) {
+	items, err := applyFirst(seq.String(), seq.items, args)
+	if err != nil {
+		return nil, false, err
+	}
 	result := seq.cloneEmpty()
+	for _, d := range items {
+		result.Append(d)
+	}
+	return result, true, nil
+}
+
+// applyFirst returns copy of `items` with `args` applied to exactly one (first) placeholder.
+func applyFirst(tag string, items []Doer, args Args) ([]Doer, error) {
+	result := make([]Doer, 0, len(items))
 	found := false
 	places := uint(0)
-	for _, child := range seq.items {
+	for _, child := range items {
 		if found {
-			result.Append(child)
+			result = append(result, child)
 			continue
 		}
-		new, applied, err := ArgApply(child, arg)
-		// log.Printf("- %s child=%s arg=%v -> applied=%t err=%v new=%#v", seq.String(), child.String(), arg, applied, err, new)
+		new, applied, err := ArgsApply(child, args)
+		// log.Printf("- %s child=%s args=%v -> applied=%t err=%v new=%#v", tag, child.String(), args, applied, err, new)
 		switch errors.Cause(err) {
 		case nil: // success path
 			places++
 			found = applied
-			result.Append(new)
+			result = append(result, new)
 
 		case ErrArgOverwrite:
 			places++
-			result.Append(child)
+			result = append(result, child)
 
 		case ErrArgNotApplied:
 			places++
-			result.Append(child)
+			result = append(result, child)
 
 		default:
-			return nil, false, errors.Annotatef(err, FmtErrContext, seq.String())
+			return nil, errors.Annotatef(err, FmtErrContext, tag)
 		}
 	}
 	if !found && places > 0 {
-		return nil, false, errors.Annotatef(ErrArgNotApplied, FmtErrContext, seq.String())
+		return nil, errors.Annotatef(ErrArgNotApplied, FmtErrContext, tag)
 	}
-	return result, true, nil
+	return result, nil
 }
 
-func (self *RestartError) Apply(arg Arg) (Doer, bool, error) {
-	new, applied, err := ArgApply(self.Doer, arg)
+func (self *RestartError) Apply(args Args) (Doer, bool, error) {
+	new, applied, err := ArgsApply(self.Doer, args)
 	if err != nil {
 		return nil, false, err
 	}
@@ -123,7 +287,7 @@ func (self *RestartError) Apply(arg Arg) (Doer, bool, error) {
 
 type IgnoreArg struct{ Doer }
 
-func (self IgnoreArg) Apply(Arg) (Doer, bool, error) { return self.Doer, true, nil }
+func (self IgnoreArg) Apply(Args) (Doer, bool, error) { return self.Doer, true, nil }
 
 // compile-time interface checks
 var _ ArgApplier = &RestartError{}
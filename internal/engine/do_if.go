@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/helpers"
+)
+
+var ErrCondFalse = errors.Errorf("condition is false")
+
+// Tester is implemented by condition actions usable in scenario `if(...)`.
+// Executed as regular action, condition works as assertion and fails with ErrCondFalse.
+type Tester interface {
+	Test(context.Context) (bool, error)
+}
+
+type Cond struct {
+	Name string
+	F    func(context.Context) (bool, error)
+}
+
+func (self Cond) Validate() error                        { return nil }
+func (self Cond) Do(ctx context.Context) error           { return condAssert(ctx, self, self.Name) }
+func (self Cond) String() string                         { return self.Name }
+func (self Cond) Test(ctx context.Context) (bool, error) { return self.F(ctx) }
+
+type CondArg struct {
+	Name string
+	F    func(context.Context, Arg) (bool, error)
+	arg  Arg
+	set  bool
+}
+
+func (self CondArg) Validate() error {
+	if !self.set {
+		return errors.Annotatef(ErrArgNotApplied, FmtErrContext, self.Name)
+	}
+	return nil
+}
+func (self CondArg) Do(ctx context.Context) error { return condAssert(ctx, self, self.String()) }
+func (self CondArg) String() string {
+	if !self.set {
+		return fmt.Sprintf("%s:Arg?", self.Name)
+	}
+	return fmt.Sprintf("%s:%v", self.Name, self.arg)
+}
+func (self CondArg) Test(ctx context.Context) (bool, error) {
+	if !self.set {
+		return false, errors.Annotatef(ErrArgNotApplied, FmtErrContext, self.Name)
+	}
+	return self.F(ctx, self.arg)
+}
+
+func (self CondArg) Apply(args Args) (Doer, bool, error) {
+	if self.set {
+		return nil, false, errors.Annotatef(ErrArgOverwrite, FmtErrContext, self.Name)
+	}
+	values, err := BindArgs(defaultParams, args)
+	if err != nil {
+		return nil, false, errors.Annotatef(err, FmtErrContext, self.Name)
+	}
+	self.arg = values[0]
+	self.set = true
+	return self, true, nil
+}
+
+func condAssert(ctx context.Context, t Tester, name string) error {
+	ok, err := t.Test(ctx)
+	if err == nil && !ok {
+		err = errors.Annotatef(ErrCondFalse, FmtErrContext, name)
+	}
+	return err
+}
+
+// If executes Then or Else depending on Cond test result.
+// Built from scenario `if(cond) ... else ... end`, `if(!cond)` negates test.
+// Else may be nil.
+type If struct {
+	Cond Doer // Tester, possibly Lazy
+	Not  bool
+	Then Doer
+	Else Doer
+}
+
+// Validate checks condition and accepts if at least one branch may run.
+// Unresolved actions are config errors and reported from any branch.
+func (self *If) Validate() error {
+	if err := self.Cond.Validate(); err != nil {
+		return errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	errs := make([]error, 0, 2)
+	for _, d := range []Doer{self.Then, self.Else} {
+		if d == nil {
+			return nil
+		}
+		if _, _, err := Force(d); err != nil {
+			return errors.Annotatef(err, FmtErrContext, self.String())
+		}
+		errs = append(errs, d.Validate())
+	}
+	if errs[0] == nil || errs[1] == nil {
+		return nil
+	}
+	return errors.Annotatef(helpers.FoldErrors(errs), FmtErrContext, self.String())
+}
+
+func (self *If) Do(ctx context.Context) error {
+	ok, err := self.test(ctx)
+	if err != nil {
+		return errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	d := self.Else
+	if ok {
+		d = self.Then
+	}
+	if d == nil {
+		return nil
+	}
+	return GetGlobal(ctx).ValidateExec(ctx, d)
+}
+
+func (self *If) String() string {
+	if self.Not {
+		return fmt.Sprintf("if(!%s)", self.Cond.String())
+	}
+	return fmt.Sprintf("if(%s)", self.Cond.String())
+}
+
+// Apply makes copy of If, applying `args` to placeholders in condition and both branches.
+func (self *If) Apply(args Args) (Doer, bool, error) {
+	result := *self
+	found := false
+	for _, b := range []*Doer{&result.Cond, &result.Then, &result.Else} {
+		if *b == nil {
+			continue
+		}
+		new, applied, err := ArgsApply(*b, args)
+		switch errors.Cause(err) {
+		case nil:
+			if applied {
+				*b = new
+				found = true
+			}
+		case ErrArgOverwrite, ErrArgNotApplied:
+		default:
+			return nil, false, errors.Annotatef(err, FmtErrContext, self.String())
+		}
+	}
+	return &result, found, nil
+}
+
+func (self *If) test(ctx context.Context) (bool, error) {
+	d, _, err := Force(self.Cond)
+	if err != nil {
+		return false, err
+	}
+	if result, ok := simTest(ctx, d); ok {
+		return result != self.Not, nil
+	}
+	t, ok := d.(Tester)
+	if !ok {
+		return false, errors.Errorf("action=%s is not condition", d.String())
+	}
+	result, err := t.Test(ctx)
+	if err != nil {
+		return false, err
+	}
+	return result != self.Not, nil
+}
+
+// compile-time interface checks
+var _ ArgApplier = &If{}
+var _ ArgApplier = CondArg{}
+var _ Tester = Cond{}
+var _ Tester = CondArg{}
+var _ Tester = &Lazy{}
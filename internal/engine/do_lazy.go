@@ -22,18 +22,37 @@ func Force(d Doer) (Doer, bool, error) {
 
 type Forcer interface{ Force() (Doer, bool, error) }
 
+// Lazy resolves action by name on first use.
+// Action with variables `foo(${x}+1)` is resolved on every Do with vars from ctx.
+// Pos is location in scenario source for errors, zero if unknown.
 type Lazy struct {
 	Name  string
+	Pos   Pos
 	mu    sync.Mutex
-	r     func(string) (Doer, error)
+	r     func(string, exprEnv) (Doer, error)
 	cache Doer
 }
 
-func (l *Lazy) Force() (d Doer, forced bool, err error) {
+func (l *Lazy) Force() (Doer, bool, error) {
+	d, forced, err := l.force()
+	if err != nil && l.Pos.Line != 0 {
+		err = errors.Annotate(err, l.Pos.String())
+	}
+	return d, forced, err
+}
+
+func (l *Lazy) force() (d Doer, forced bool, err error) {
+	if hasVars(l.Name) {
+		// only check action exists and expression syntax, value is known in Do
+		if _, err = l.r(l.Name, exprEnv{check: true}); err != nil {
+			return nil, false, err
+		}
+		return l, false, nil
+	}
 	l.mu.Lock()
 	d = l.cache
 	if d == nil {
-		d, err = l.r(l.Name)
+		d, err = l.r(l.Name, exprEnv{})
 		if err == nil {
 			// log.Printf("lazy.force store %#v", d)
 			l.cache = d
@@ -45,20 +64,41 @@ func (l *Lazy) Force() (d Doer, forced bool, err error) {
 
 func (l *Lazy) Validate() error {
 	d, _, err := l.Force()
-	if err != nil {
+	if err != nil || d == l {
 		return err
 	}
 	return d.Validate()
 }
 
 func (l *Lazy) Do(ctx context.Context) error {
-	d, _, err := l.Force()
+	d, err := l.resolve(ctx)
 	if err != nil {
 		return err
 	}
 	return GetGlobal(ctx).ExecPart(ctx, d)
 }
 
+// Test makes Lazy usable as `if` condition, resolved action must be Tester.
+func (l *Lazy) Test(ctx context.Context) (bool, error) {
+	d, err := l.resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	t, ok := d.(Tester)
+	if !ok {
+		return false, errors.Errorf("action=%s is not condition", d.String())
+	}
+	return t.Test(ctx)
+}
+
+func (l *Lazy) resolve(ctx context.Context) (Doer, error) {
+	if hasVars(l.Name) {
+		return l.r(l.Name, exprEnv{vars: GetGlobal(ctx).currentVars(ctx)})
+	}
+	d, _, err := l.Force()
+	return d, err
+}
+
 func (l *Lazy) String() string { return l.Name }
 
 func (seq *Seq) Force() (Doer, bool, error) {
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/helpers"
+)
+
+// Parallel executor, scenario `par(a b c)`.
+// All children are started at once, Par waits for all of them and folds errors.
+// Commands to one MDB device are still serialized by device command lock,
+// so useful children work with different devices.
+type Par struct {
+	items []Doer
+}
+
+func NewPar(ds ...Doer) *Par { return &Par{items: ds} }
+
+func (self *Par) Append(d Doer) *Par {
+	self.items = append(self.items, d)
+	return self
+}
+
+func (self *Par) Validate() error {
+	errs := make([]error, 0, len(self.items))
+	for _, d := range self.items {
+		if err := d.Validate(); err != nil {
+			err = errors.Annotatef(err, "par node=%s validate", d.String())
+			errs = append(errs, err)
+		}
+	}
+	return helpers.FoldErrors(errs)
+}
+
+func (self *Par) Do(ctx context.Context) error {
+	e := GetGlobal(ctx)
+	errch := make(chan error, len(self.items))
+	wg := sync.WaitGroup{}
+	simCtxs := simFork(ctx, len(self.items))
+	for i, d := range self.items {
+		d, itemCtx := d, ctx
+		if simCtxs != nil {
+			itemCtx = simCtxs[i]
+		}
+		wg.Add(1)
+		go helpers.WrapErrChan(&wg, errch, func() error { return e.Exec(itemCtx, d) })
+	}
+	wg.Wait()
+	simJoin(ctx, simCtxs)
+	close(errch)
+	return helpers.FoldErrChan(errch)
+}
+
+func (self *Par) String() string {
+	ss := make([]string, len(self.items))
+	for i, d := range self.items {
+		ss[i] = d.String()
+	}
+	return "par(" + strings.Join(ss, " ") + ")"
+}
+
+// Apply makes copy of Par, applying `args` to exactly one (first) placeholder.
+func (self *Par) Apply(args Args) (Doer, bool, error) {
+	items, err := applyFirst(self.String(), self.items, args)
+	if err != nil {
+		return nil, false, err
+	}
+	return &Par{items: items}, true, nil
+}
+
+func (self *Par) Force() (Doer, bool, error) {
+	result := &Par{items: make([]Doer, 0, len(self.items))}
+	forcedAny := false
+	for _, child := range self.items {
+		new, forced, err := Force(child)
+		if err != nil {
+			return nil, forced, errors.Annotatef(err, FmtErrContext, child.String())
+		}
+		forcedAny = forcedAny || forced
+		result.Append(new)
+	}
+	if !forcedAny {
+		return self, false, nil
+	}
+	return result, true, nil
+}
+
+// compile-time interface checks
+var _ ArgApplier = &Par{}
+var _ Forcer = &Par{}
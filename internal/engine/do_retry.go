@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Retry executes D again after error, up to N attempts total.
+// Scenario `retry(3, a b)`. Stops early when ctx is done.
+// Failed attempts are logged, returned error names the last attempt.
+// Completed steps of failed attempt are compensated before next one, see Saga.
+type Retry struct {
+	N uint
+	D Doer
+}
+
+func (self *Retry) Validate() error { return self.D.Validate() }
+func (self *Retry) Do(ctx context.Context) error {
+	e := GetGlobal(ctx)
+	parent := getSaga(ctx)
+	var err error
+	for i := uint(1); i <= self.N; i++ {
+		attemptCtx, attempt := ctx, (*Saga)(nil)
+		if parent != nil {
+			attemptCtx, attempt = WithSaga(ctx)
+		}
+		err = e.Exec(attemptCtx, self.D)
+		if err == nil || i == self.N || ctx.Err() != nil {
+			if attempt != nil {
+				parent.merge(attempt)
+			}
+			if err != nil {
+				err = errors.Annotatef(err, "%s attempt=%d/%d", self.String(), i, self.N)
+			}
+			return err
+		}
+		e.Log.Errorf("%s attempt=%d/%d err=%v", self.String(), i, self.N, err)
+		if attempt != nil {
+			if rbErr := attempt.Rollback(ctx); rbErr != nil {
+				e.Log.Errorf("%s attempt=%d/%d rollback err=%v", self.String(), i, self.N, rbErr)
+			}
+		}
+	}
+	return err
+}
+func (self *Retry) String() string { return fmt.Sprintf("retry(%d, %s)", self.N, self.D.String()) }
+
+func (self *Retry) Apply(args Args) (Doer, bool, error) {
+	new, applied, err := ArgsApply(self.D, args)
+	if err != nil {
+		return nil, false, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	return &Retry{N: self.N, D: new}, applied, nil
+}
+
+func (self *Retry) Force() (Doer, bool, error) {
+	new, forced, err := Force(self.D)
+	if err != nil {
+		return nil, forced, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	if !forced {
+		return self, false, nil
+	}
+	return &Retry{N: self.N, D: new}, true, nil
+}
+
+// Timeout executes D with deadline, scenario `timeout(5s, a b)`.
+// D runs synchronously and must observe ctx to be interrupted.
+type Timeout struct {
+	Duration time.Duration
+	D        Doer
+}
+
+func (self *Timeout) Validate() error { return self.D.Validate() }
+func (self *Timeout) Do(ctx context.Context) error {
+	tctx, cancel := context.WithTimeout(ctx, self.Duration)
+	defer cancel()
+	err := GetGlobal(ctx).Exec(tctx, self.D)
+	if err != nil && tctx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		err = errors.Annotatef(err, "%s expired", self.String())
+	}
+	return err
+}
+func (self *Timeout) String() string {
+	return fmt.Sprintf("timeout(%v, %s)", self.Duration, self.D.String())
+}
+
+func (self *Timeout) Apply(args Args) (Doer, bool, error) {
+	new, applied, err := ArgsApply(self.D, args)
+	if err != nil {
+		return nil, false, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	return &Timeout{Duration: self.Duration, D: new}, applied, nil
+}
+
+func (self *Timeout) Force() (Doer, bool, error) {
+	new, forced, err := Force(self.D)
+	if err != nil {
+		return nil, forced, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	if !forced {
+		return self, false, nil
+	}
+	return &Timeout{Duration: self.Duration, D: new}, true, nil
+}
+
+func (self *Retry) Children() []Doer   { return []Doer{self.D} }
+func (self *Timeout) Children() []Doer { return []Doer{self.D} }
+
+// compile-time interface checks
+var _ ArgApplier = &Retry{}
+var _ ArgApplier = &Timeout{}
+var _ Composite = &Retry{}
+var _ Composite = &Timeout{}
+var _ Forcer = &Retry{}
+var _ Forcer = &Timeout{}
@@ -64,6 +64,80 @@ func TestArg(t *testing.T) {
 	}
 }
 
+func TestArgsBind(t *testing.T) {
+	t.Parallel()
+
+	params := []Param{
+		{Name: "pos", Max: 2000},
+		{Name: "speed", Min: 1, Max: 255, Optional: true, Default: 100},
+	}
+	cases := []struct {
+		input  string
+		expect []Arg
+		err    string
+	}{
+		{"1210", []Arg{1210, 100}, ""},
+		{"1210,200", []Arg{1210, 200}, ""},
+		{"pos=1210,speed=200", []Arg{1210, 200}, ""},
+		{"speed=200,pos=-0", []Arg{0, 200}, ""},
+		{"1210,speed=7", []Arg{1210, 7}, ""},
+		{"speed=7", nil, "argument=pos missing"},
+		{"1210,300", nil, "argument=speed value=300 out of range 1..255"},
+		{"1,2,3", nil, "arguments count=3 max=2"},
+		{"1,pos=2", nil, "argument=pos duplicate"},
+		{"1,size=2", nil, "argument=size unknown"},
+		{"pos=1,2", nil, "positional after named"},
+		{"pos=x", nil, "argument=pos=x value"},
+		{"=5", nil, "argument==5 name"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.input, func(t *testing.T) {
+			args, err := ParseArgs(c.input)
+			var result []Arg
+			if err == nil {
+				result, err = BindArgs(params, args)
+			}
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, result)
+		})
+	}
+}
+
+func TestArgsResolve(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	var result []Arg
+	e.Register("move(?)", FuncArg{
+		Name:   "move",
+		Params: []Param{{Name: "pos"}, {Name: "speed", Optional: true}},
+		FArgs:  func(_ context.Context, args []Arg) error { result = args; return nil },
+	})
+	require.NoError(t, e.RegisterParse("fast(?)", "ignore(1) move(?)"))
+
+	e.TestDo(t, ctx, "move(pos=10,speed=3)")
+	assert.Equal(t, []Arg{10, 3}, result)
+	e.TestDo(t, ctx, "fast(20,speed=5)")
+	assert.Equal(t, []Arg{20, 5}, result)
+	e.TestDo(t, ctx, "fast(30)")
+	assert.Equal(t, []Arg{30, 0}, result)
+	assert.Equal(t, "move:30,0", e.Resolve("move(30)").String())
+
+	assert.Error(t, e.Resolve("ignore(1,2)").Validate())
+	assert.Error(t, e.Resolve("fast(speed=1)").Validate())
+
+	// zero-arity action must use FArgs, F has no argument to receive
+	e.Register("nop(?)", FuncArg{Name: "nop", Params: []Param{}, F: func(context.Context, Arg) error { return nil }})
+	assert.Error(t, e.Resolve("nop(1)").Validate())
+	assert.Error(t, FuncArg{Name: "nop", Params: []Param{}, F: func(context.Context, Arg) error { return nil }, set: true}.Do(ctx))
+}
+
 // Few actions in sequence is a common case worth optimizing.
 func BenchmarkSequentialDo(b *testing.B) {
 	mkbench := func(kind string, length int) func(b *testing.B) {
@@ -128,3 +202,80 @@ func (self *mockdo) Do(ctx context.Context) error {
 	return self.err
 }
 func (self *mockdo) String() string { return self.name }
+
+func TestEvalExpr(t *testing.T) {
+	t.Parallel()
+
+	vars := Vars{"water": 150, "size": 2}
+	cases := []struct {
+		input  string
+		expect Arg
+		err    string
+	}{
+		{"42", 42, ""},
+		{"${water}*12/10", 180, ""},
+		{"${water} + ${size} * 10", 170, ""},
+		{"(${water} + ${size}) * 10", 1520, ""},
+		{"-${size} - -3", 1, ""},
+		{"17 % 5", 2, ""},
+		{"${milk}", 0, "variable=milk not found"},
+		{"${water}/(${size}-2)", 0, "division by zero"},
+		{"3000000000", 0, "overflow"},
+		{"65536*65536", 0, "overflow"},
+		{"2+", 0, "unexpected end"},
+		{"(2", 0, "missing )"},
+		{"2x", 0, "unexpected=x"},
+		{"$water", 0, "expected ${name}"},
+	}
+	for _, c := range cases {
+		result, err := EvalExpr(c.input, vars)
+		if c.err == "" {
+			assert.NoError(t, err, c.input)
+			assert.Equal(t, c.expect, result, c.input)
+		} else {
+			require.Error(t, err, c.input)
+			assert.Contains(t, err.Error(), c.err, c.input)
+		}
+	}
+}
+
+func TestVars(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	var result []Arg
+	e.Register("pour(?)", FuncArg{
+		Name:   "pour",
+		Params: []Param{{Name: "ml"}, {Name: "speed", Optional: true}},
+		FArgs:  func(_ context.Context, args []Arg) error { result = args; return nil },
+	})
+	e.SetVars(Vars{"speed": 5})
+	require.NoError(t, e.RegisterParse("make", "pour(${water} * 12 / 10, speed=${speed})"))
+	d, err := e.ParseText("menu", "make")
+	require.NoError(t, err)
+	require.NoError(t, d.Validate())
+
+	err = e.Exec(ctx, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "variable=water not found")
+
+	require.NoError(t, e.Exec(ctx, &Let{Vars: Vars{"water": 150}, D: d}))
+	assert.Equal(t, []Arg{180, 5}, result)
+	require.NoError(t, e.Exec(WithVars(ctx, Vars{"water": 200, "speed": 7}), d))
+	assert.Equal(t, []Arg{240, 7}, result)
+
+	bad, err := e.ParseText("bad", "pour(${water}*)")
+	require.NoError(t, err)
+	assert.Error(t, bad.Validate())
+	typo, err := e.ParseText("typo", "poor(${water})")
+	require.NoError(t, err)
+	assert.Error(t, typo.Validate())
+
+	// value errors depend on vars, only known in Exec
+	div, err := e.ParseText("div", "pour(100/(${n}-1)) pour(${x}*3000000000)")
+	require.NoError(t, err)
+	require.NoError(t, div.Validate())
+	err = e.Exec(WithVars(ctx, Vars{"n": 1, "x": 0}), div)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "division by zero")
+}
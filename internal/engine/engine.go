@@ -4,12 +4,10 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
-	"github.com/temoto/vender/helpers"
 	"github.com/temoto/vender/log2"
 )
 
@@ -24,15 +22,36 @@ type Engine struct {
 	Log     *log2.Log
 	lk      sync.RWMutex
 	actions map[string]Doer
+	meta    map[string]Meta
+	vars    Vars
 	profile struct {
 		// optimistic field access guard; fastpath=0 -> profiling disabled, don't touch mutex
 		fastpath uint32
 
 		sync.Mutex // fields below access guard
 
-		re  *regexp.Regexp
-		min time.Duration
-		fun ProfileFunc
+		re   *regexp.Regexp
+		min  time.Duration
+		fun  ProfileFunc
+		hist map[string]*latencyHist // by action name, see TakeLatency
+	}
+	abort struct {
+		sync.Mutex
+		seq    uint64
+		cancel context.CancelFunc
+	}
+	trace struct {
+		sync.Mutex
+		ring []*Span
+		next int
+	}
+	hooks struct {
+		sync.Mutex
+		m map[string][]Doer // event name or name:subject
+	}
+	parseCache struct {
+		sync.Mutex
+		m map[parseCacheKey]Doer
 	}
 }
 
@@ -40,25 +59,33 @@ func NewEngine(log *log2.Log) *Engine {
 	self := &Engine{
 		Log:     log,
 		actions: make(map[string]Doer, 128),
+		meta:    make(map[string]Meta, 128),
 	}
-	self.actions["ignore(?)"] = FuncArg{
+	self.Register("ignore(?)", FuncArg{
 		Name: "ignore(?)",
-		F:    func(context.Context, Arg) error { return nil }}
-	self.actions["sleep(100ms)"] = Sleep{Duration: 100 * time.Millisecond}
+		F:    func(context.Context, Arg) error { return nil }},
+		Meta{Description: "does nothing, consumes argument", Safe: true})
+	self.Register("sleep(100ms)", Sleep{Duration: 100 * time.Millisecond},
+		Meta{Description: "pause, any duration `sleep(1500ms)`", Safe: true})
 	return self
 }
 
-func (self *Engine) Register(action string, d Doer) {
+// Register adds action, optional meta describes it for Describe and Catalog.
+func (self *Engine) Register(action string, d Doer, meta ...Meta) {
 	self.lk.Lock()
 	self.actions[action] = d
+	if len(meta) != 0 {
+		self.meta[action] = meta[0]
+	}
 	self.lk.Unlock()
+	self.parseCacheReset()
 }
 
-func (self *Engine) RegisterNewFunc(name string, fun func(context.Context) error) {
+func (self *Engine) RegisterNewFunc(name string, fun func(context.Context) error, meta ...Meta) {
 	self.Register(name, Func{
 		Name: name,
 		F:    fun,
-	})
+	}, meta...)
 }
 
 func (self *Engine) RegisterNewSeq(name string, ds ...Doer) {
@@ -79,13 +106,17 @@ func (self *Engine) RegisterParse(name, scenario string) error {
 	return nil
 }
 
-var reActionArg = regexp.MustCompile(`^(.+)\((\d+|\?)\)$`)
+var reActionArg = regexp.MustCompile(`^([^()]+)\((.+)\)$`)
 
 func (self *Engine) resolve(action string) (Doer, error) {
+	return self.resolveEnv(action, exprEnv{})
+}
+
+func (self *Engine) resolveEnv(action string, env exprEnv) (Doer, error) {
 	// self.Log.Debugf("engine.resolve action=%s", action)
 	self.lk.RLock()
 	defer self.lk.RUnlock()
-	return self.locked_resolve(action)
+	return self.locked_resolve(action, env)
 }
 
 type token struct {
@@ -108,7 +139,9 @@ func parseArg(s string) token {
 	}
 }
 
-func (self *Engine) locked_resolve(action string) (Doer, error) {
+// locked_resolve evaluates argument expressions with env.
+// env.check only validates syntax and returns Doer without arguments applied.
+func (self *Engine) locked_resolve(action string, env exprEnv) (Doer, error) {
 	d, ok := self.actions[action]
 	if ok {
 		// self.Log.Debugf("engine.resolve action=%s resolved d=%v", action, d)
@@ -130,13 +163,16 @@ func (self *Engine) locked_resolve(action string) (Doer, error) {
 		return nil, err
 	}
 	if tok.arg != "?" {
-		argn, err := strconv.Atoi(tok.arg)
+		args, err := parseArgs(tok.arg, env)
 		if err != nil {
 			self.Log.Debugf("resolve action=%s err=%s", action, err)
 			return nil, errors.Annotatef(err, FmtErrContext, action)
 		}
+		if env.check {
+			return d, nil
+		}
 		var applied bool
-		d, applied, err = ArgApply(d, Arg(argn))
+		d, applied, err = ArgsApply(d, args)
 		if err != nil {
 			self.Log.Debugf("resolve action=%s err=%s", action, err)
 			return nil, errors.Annotatef(err, FmtErrContext, action)
@@ -189,29 +225,16 @@ func (self *Engine) ResolveOrLazy(action string) (Doer, error) {
 	}
 
 	// self.Log.Debugf("engine.ResolveOrLazy %s -> lazy %#v", action, d)
-	return &Lazy{Name: action, r: self.resolve}, nil
+	return &Lazy{Name: action, r: self.resolveEnv}, nil
 }
 
-var reNotSpace = regexp.MustCompile(`\S+`)
-
-func (self *Engine) ParseText(tag, text string) (Doer, error) {
-	// TODO cache with github.com/hashicorp/golang-lru
-
-	errs := make([]error, 0)
-	words := reNotSpace.FindAllString(text, -1)
+func (self *Engine) Exec(ctx context.Context, d Doer) error { return self.exec(ctx, d, false, true) }
 
-	tx := NewSeq(tag)
-	for _, word := range words {
-		d, err := self.ResolveOrLazy(word)
-		if err != nil {
-			return nil, errors.Annotatef(err, "scenario=%s unparsed=%s", text, word)
-		}
-		tx.Append(d)
-	}
-	return tx, helpers.FoldErrors(errs)
+// ExecTrace is Exec recording trace tree of d and nested actions.
+// Plain Exec records root spans only when trace buffer is set, see SetTraceBuffer.
+func (self *Engine) ExecTrace(ctx context.Context, d Doer) (*Span, error) {
+	return self.execTrace(ctx, d, false, true, true)
 }
-
-func (self *Engine) Exec(ctx context.Context, d Doer) error { return self.exec(ctx, d, false, true) }
 func (self *Engine) ExecPart(ctx context.Context, d Doer) error {
 	return self.exec(ctx, d, false, false)
 }
@@ -236,25 +259,55 @@ func (self *Engine) ExecList(ctx context.Context, tag string, list []string) []e
 	return errs
 }
 
-func (self *Engine) exec(ctx context.Context, d Doer, validate, enableProfile bool) (err error) {
+func (self *Engine) exec(ctx context.Context, d Doer, validate, enableProfile bool) error {
+	_, err := self.execTrace(ctx, d, validate, enableProfile, false)
+	return err
+}
+
+func (self *Engine) execTrace(ctx context.Context, d Doer, validate, enableProfile, trace bool) (span *Span, err error) {
+	parent := getSpan(ctx)
+	// nested Lazy executes resolved Doer, which gets its own span
+	_, isLazy := d.(*Lazy)
+	if (parent != nil && !isLazy) || (parent == nil && (trace || self.traceEnabled())) {
+		span = newSpan(d)
+		ctx = context.WithValue(ctx, spanContextKey, span)
+		if parent != nil {
+			parent.add(span)
+		}
+		defer func() {
+			span.finish(err)
+			if parent == nil {
+				self.traceStore(span)
+			}
+		}()
+	}
+
 	if validate {
 		err = d.Validate()
 	}
+	if err == nil {
+		err = ctx.Err()
+	}
 	if err == nil {
 		if enableProfile {
 			tag := d.String() // FIXME faster .Tag() or cache result
-			if profFun, profMin := self.matchProfile(tag); profFun != nil {
+			if profFun, profMin, ok := self.matchProfile(tag); ok {
 				tbegin := time.Now()
 				defer func() {
-					if duration := time.Since(tbegin); duration >= profMin {
+					duration := time.Since(tbegin)
+					name, _ := doerName(d)
+					self.profileRecord(name, duration)
+					if profFun != nil && duration >= profMin {
 						profFun(d, duration)
 					}
 				}()
 			}
 		}
-		err = d.Do(ctx)
+		if !simDo(ctx, d) {
+			err = d.Do(ctx)
+		}
 	}
-	return err
+	return span, err
 }
 
 // Test `error` or `Doer` against ErrNotResolved
@@ -1,8 +1,12 @@
 package engine
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 
@@ -88,6 +92,274 @@ func TestParseText(t *testing.T) {
 	assert.Equal(t, int32(2), doHello.called)
 }
 
+func TestParseIf(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	var flag bool
+	e.Register("flag", Cond{Name: "flag", F: func(context.Context) (bool, error) { return flag, nil }})
+	e.Register("ge(?)", CondArg{Name: "ge", F: func(_ context.Context, a Arg) (bool, error) { return a >= 10, nil }})
+	result := ""
+	for _, s := range []string{"a", "b", "c", "d"} {
+		s := s
+		e.RegisterNewFunc(s, func(context.Context) error { result += s; return nil })
+	}
+	require.NoError(t, e.RegisterParse("root(?)", "a if(flag) b if(!ge(5)) c else ignore(?) end else d end a"))
+
+	cases := []struct {
+		flag   bool
+		expect string
+	}{
+		{false, "ada"},
+		{true, "abca"},
+	}
+	for _, c := range cases {
+		flag = c.flag
+		result = ""
+		e.TestDo(t, ctx, "root(7)")
+		assert.Equal(t, c.expect, result, "flag=%t", c.flag)
+	}
+
+	for _, bad := range []string{"a end", "if(flag) a", "if(flag) a else b", "a else b"} {
+		_, err := e.ParseText("bad", bad)
+		assert.Error(t, err, bad)
+	}
+
+	e.RegisterNewFunc("notcond", func(context.Context) error { return nil })
+	d, err := e.ParseText("notcond", "if(notcond) a end")
+	require.NoError(t, err)
+	assert.Contains(t, e.Exec(ctx, d).Error(), "is not condition")
+
+	d, err = e.ParseText("assert", "flag")
+	require.NoError(t, err)
+	flag = false
+	assert.Equal(t, ErrCondFalse, errors.Cause(e.Exec(ctx, d)))
+}
+
+func TestIfVars(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	e.Register("ge(?)", CondArg{Name: "ge", F: func(_ context.Context, a Arg) (bool, error) { return a >= 10, nil }})
+	result := ""
+	for _, s := range []string{"a", "b"} {
+		s := s
+		e.RegisterNewFunc(s, func(context.Context) error { result += s; return nil })
+	}
+	d, err := e.ParseText("size", "if(ge(${size}*2)) a else b end")
+	require.NoError(t, err)
+	require.NoError(t, d.Validate())
+
+	for _, c := range []struct {
+		size   Arg
+		expect string
+	}{{3, "b"}, {5, "a"}} {
+		result = ""
+		require.NoError(t, e.Exec(WithVars(ctx, Vars{"size": c.size}), d))
+		assert.Equal(t, c.expect, result, "size=%d", c.size)
+	}
+	err = e.Exec(ctx, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "variable=size not found")
+}
+
+func TestIfApplyCond(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	e.Register("ge(?)", CondArg{Name: "ge", F: func(_ context.Context, a Arg) (bool, error) { return a >= 10, nil }})
+	result := ""
+	for _, s := range []string{"a", "b"} {
+		s := s
+		e.RegisterNewFunc(s, func(context.Context) error { result += s; return nil })
+	}
+	require.NoError(t, e.RegisterParse("big(?)", "if(ge(?)) a else b end"))
+
+	for _, c := range []struct {
+		input  string
+		expect string
+	}{{"big(3)", "b"}, {"big(12)", "a"}} {
+		result = ""
+		e.TestDo(t, ctx, c.input)
+		assert.Equal(t, c.expect, result, c.input)
+	}
+}
+
+func TestIfValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	e.Register("yes", Cond{Name: "yes", F: func(context.Context) (bool, error) { return true, nil }})
+	e.Register("broken", Fail{E: errors.New("broken")})
+	e.Register("ok", Nothing{Name: "ok"})
+
+	d, err := e.ParseText("fallback", "if(yes) broken else ok end")
+	require.NoError(t, err)
+	require.NoError(t, d.Validate(), "one valid branch is enough")
+	assert.Contains(t, e.Exec(ctx, d).Error(), "broken")
+
+	d, err = e.ParseText("both", "if(yes) broken else broken end")
+	require.NoError(t, err)
+	require.Error(t, d.Validate())
+
+	d, err = e.ParseText("typo", "if(yes) ok else typo end")
+	require.NoError(t, err)
+	err = d.Validate()
+	require.Error(t, err, "typo in unused branch")
+	assert.Contains(t, err.Error(), "action=typo not resolved")
+}
+
+func TestParsePar(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	var mu sync.Mutex
+	result := map[string]Arg{}
+	e.Register("put(?)", FuncArg{Name: "put", F: func(_ context.Context, a Arg) error {
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		result[fmt.Sprint(len(result))] = a
+		mu.Unlock()
+		return nil
+	}})
+	e.RegisterNewFunc("fail1", func(context.Context) error { return errors.New("fail1") })
+	e.RegisterNewFunc("fail2", func(context.Context) error { return errors.New("fail2") })
+	require.NoError(t, e.RegisterParse("root(?)", "par(put(?) put(2)\n  put(3)) ignore(0)"))
+
+	tbegin := time.Now()
+	e.TestDo(t, ctx, "root(1)")
+	assert.Less(t, int64(time.Since(tbegin)), int64(140*time.Millisecond))
+	assert.Len(t, result, 3)
+
+	d, err := e.ParseText("errors", "par(fail1 ignore(1) fail2)")
+	require.NoError(t, err)
+	err = e.Exec(ctx, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fail1")
+	assert.Contains(t, err.Error(), "fail2")
+
+	for _, bad := range []string{"par(fail1", "fail1)", "par(if(fail1) fail2)"} {
+		_, err := e.ParseText("bad", bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseRetryTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	calls := 0
+	e.Register("flaky(?)", FuncArg{Name: "flaky", F: func(_ context.Context, a Arg) error {
+		calls++
+		if calls < int(a) {
+			return errors.Errorf("flaky call=%d", calls)
+		}
+		return nil
+	}})
+	require.NoError(t, e.RegisterParse("root(?)", "retry(3, flaky(?)) timeout(1s, sleep(100ms))"))
+	e.TestDo(t, ctx, "root(3)")
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	d, err := e.ParseText("fail", "retry(2,flaky(5))")
+	require.NoError(t, err)
+	err = e.Exec(ctx, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry(2, flaky(5)) attempt=2/2: flaky call=2")
+
+	d, err = e.ParseText("slow", "timeout(20ms, ignore(1) sleep(100ms))")
+	require.NoError(t, err)
+	tbegin := time.Now()
+	err = e.Exec(ctx, d)
+	require.Error(t, err)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	assert.Contains(t, err.Error(), "timeout(20ms, ignore(1) Sleep(100ms)) expired")
+	assert.Less(t, int64(time.Since(tbegin)), int64(90*time.Millisecond))
+
+	for _, bad := range []string{"retry(0, ignore(1))", "retry(x, ignore(1))", "timeout(5, ignore(1))", "retry(2, ignore(1)", "timeout(1s, )"} {
+		_, err := e.ParseText("bad", bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	_, e := newTestContext(t)
+	cases := []struct {
+		src    Source
+		expect string
+	}{
+		{Source{Tag: "t", Text: "a\n  b end"}, "2:5 scenario=t word=end: unexpected"},
+		{Source{Tag: "t", Text: "a par(b c"}, "1:3 scenario=t word=par(: missing )"},
+		{Source{Tag: "t", Text: "a\nif(c) b", Pos: Pos{File: "vender.hcl", Line: 10, Col: 20}}, "vender.hcl:11:1 scenario=t word=if(c): missing end"},
+		{Source{Tag: "t", Text: "a retry(x, b)", Pos: Pos{File: "vender.hcl", Line: 10, Col: 20}}, "vender.hcl:10:22 scenario=t word=retry(x,: retry count=x not valid"},
+		{Source{Tag: "t", Text: "timeout(1s, )"}, "1:1 scenario=t word=timeout(1s,: timeout() is empty"},
+	}
+	for _, c := range cases {
+		_, err := e.ParseSource(c.src)
+		require.Error(t, err, c.src.Text)
+		assert.Equal(t, c.expect, err.Error())
+		_, ok := err.(*ParseError)
+		assert.True(t, ok, "error type=%T", err)
+	}
+
+	nodes, err := ParseAST(Source{Text: "a if(!c)\n  par(b d)\nend"})
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, NodeIf, nodes[1].Kind)
+	assert.Equal(t, "c", nodes[1].Name)
+	assert.True(t, nodes[1].Not)
+	require.Len(t, nodes[1].Body, 1)
+	assert.Equal(t, Pos{Line: 2, Col: 3}, nodes[1].Body[0].Pos)
+	assert.Equal(t, Pos{Line: 2, Col: 7}, nodes[1].Body[0].Body[0].Pos)
+}
+
+func TestParseCache(t *testing.T) {
+	t.Parallel()
+
+	_, e := newTestContext(t)
+	e.Register("a", Nothing{Name: "a"})
+	d1, err := e.ParseText("t", "a b")
+	require.NoError(t, err)
+	d2, err := e.ParseText("t", "a b")
+	require.NoError(t, err)
+	assert.True(t, d1 == d2, "second parse must be cached")
+	d3, err := e.ParseText("other", "a b")
+	require.NoError(t, err)
+	assert.Equal(t, "other", d3.String())
+
+	e.Register("b", Nothing{Name: "b"})
+	d4, err := e.ParseText("t", "a b")
+	require.NoError(t, err)
+	assert.True(t, d1 != d4, "Register must reset cache")
+	require.NoError(t, d4.Validate())
+}
+
+func TestAbort(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	assert.False(t, e.Abort(), "nothing running")
+	cleanup := false
+	e.RegisterNewFunc("cleanup", func(ctx context.Context) error { cleanup = ctx.Err() == nil; return nil })
+	d, err := e.ParseText("stuck", "ignore(1) sleep(10s)")
+	require.NoError(t, err)
+
+	abortCtx, release := e.WithAbort(ctx)
+	time.AfterFunc(20*time.Millisecond, func() { assert.True(t, e.Abort()) })
+	tbegin := time.Now()
+	err = e.Exec(abortCtx, d)
+	release()
+	require.Error(t, err)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+	assert.Less(t, int64(time.Since(tbegin)), int64(time.Second))
+	assert.False(t, e.Abort(), "released")
+
+	require.NoError(t, e.Exec(Detach(abortCtx), e.Resolve("cleanup")))
+	assert.True(t, cleanup)
+}
+
 func TestRegisterNewFunc(t *testing.T) {
 	t.Parallel()
 
@@ -122,3 +394,233 @@ func newTestContext(t testing.TB) (context.Context, *Engine) {
 	ctx = context.WithValue(ctx, ContextKey, e)
 	return ctx, e
 }
+
+func TestSaga(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	result := ""
+	for _, s := range []string{"a", "b", "c", "ua", "ub", "uc", "uab"} {
+		s := s
+		e.RegisterNewFunc(s, func(context.Context) error { result += s + " "; return nil })
+	}
+	failOnce := 0
+	e.RegisterNewFunc("fail", func(context.Context) error { result += "fail "; return errors.New("fail") })
+	e.RegisterNewFunc("flaky", func(context.Context) error {
+		failOnce++
+		if failOnce == 1 {
+			return errors.New("flaky")
+		}
+		return nil
+	})
+	compensate := func(name, scenario, undo string) {
+		d, err := e.ParseText(name, scenario)
+		require.NoError(t, err)
+		u, err := e.ParseText(name+"/undo", undo)
+		require.NoError(t, err)
+		e.Register(name, &Compensate{D: d, Undo: u})
+	}
+	compensate("step_a", "a", "ua")
+	compensate("step_b", "b", "ub")
+	compensate("step_c", "c flaky", "uc")
+	compensate("step_ab", "step_a step_b", "uab")
+
+	cases := []struct {
+		scenario string
+		expect   string
+	}{
+		{"step_a step_b fail", "a b fail | ub ua "},
+		{"step_ab fail", "a b fail | uab "},
+		{"step_a par(step_b sleep(1ms)) fail", "a b fail | ub ua "},
+		{"retry(2, step_a step_c) fail", "a c ua a c fail | uc ua "},
+	}
+	for _, c := range cases {
+		d, err := e.ParseText("root", c.scenario)
+		require.NoError(t, err)
+		result, failOnce = "", 0
+		sagaCtx, saga := WithSaga(ctx)
+		require.Error(t, e.Exec(sagaCtx, d), c.scenario)
+		result += "| "
+		require.NoError(t, saga.Rollback(sagaCtx))
+		assert.Equal(t, c.expect, result, c.scenario)
+		assert.Equal(t, 0, saga.Len())
+	}
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	e.RegisterNewFunc("prepare", func(context.Context) error { return nil })
+	e.Register("move(?)", FuncArg{Name: "move", F: func(context.Context, Arg) error { return errors.New("mdb timeout") }})
+	require.NoError(t, e.RegisterParse("make", "prepare move(3)"))
+	d, err := e.ParseText("root", "make")
+	require.NoError(t, err)
+
+	span, err := e.ExecTrace(ctx, d)
+	require.Error(t, err)
+	require.NotNil(t, span)
+	t.Log(span.String())
+	assert.Equal(t, "root", span.Name)
+	assert.Equal(t, err.Error(), span.Error)
+	failed := span.Failed()
+	require.NotNil(t, failed)
+	assert.Equal(t, "move", failed.Name)
+	assert.Equal(t, "3", failed.Args)
+	assert.Equal(t, "mdb timeout", failed.Error)
+	require.NoError(t, e.Exec(ctx, e.Resolve("prepare")))
+	assert.Empty(t, e.Traces(), "tracing every Exec is opt-in")
+
+	e.SetTraceBuffer(2)
+	require.NoError(t, e.Exec(ctx, e.Resolve("prepare")))
+	require.NoError(t, e.Exec(ctx, e.Resolve("prepare")))
+	traces := e.Traces()
+	require.Equal(t, 2, len(traces))
+	assert.Equal(t, "prepare", traces[0].Name)
+	assert.Nil(t, traces[1].Failed())
+}
+
+func TestSimulation(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	device := func(context.Context) error { return errors.New("hardware must not run in simulation") }
+	e.RegisterNewFunc("cup", device)
+	e.RegisterNewFunc("ready", device)
+	e.Register("move(?)", FuncArg{Name: "move", F: func(context.Context, Arg) error { return device(ctx) }})
+	e.Register("warm", Cond{Name: "warm", F: func(context.Context) (bool, error) { return false, errors.New("hw") }})
+	models := make([]SimModel, 0)
+	for _, x := range []struct {
+		match, duration, perArg string
+		result                  bool
+	}{
+		{"cup|ready", "2s", "", false},
+		{"move", "", "10ms", false},
+		{"warm", "", "", true},
+	} {
+		m, err := ParseSimModel(x.match, x.duration, x.perArg, x.result)
+		require.NoError(t, err)
+		models = append(models, m)
+	}
+	sim := NewSimulation(models...)
+
+	cases := []struct {
+		scenario string
+		expect   time.Duration
+	}{
+		{"cup move(300) sleep(1s)", 6 * time.Second},
+		{"par(cup move(100)) cup", 4 * time.Second},
+		{"if(warm) move(50) else cup end", 500 * time.Millisecond},
+		{"retry(2, ready)", 2 * time.Second},
+	}
+	for _, c := range cases {
+		d, err := e.ParseText("root", c.scenario)
+		require.NoError(t, err)
+		simCtx, result := WithSimulation(ctx, sim)
+		tbegin := time.Now()
+		require.NoError(t, e.Exec(simCtx, d), c.scenario)
+		assert.Less(t, int64(time.Since(tbegin)), int64(time.Second), "virtual clock")
+		assert.Equal(t, c.expect, result.Duration(), c.scenario)
+	}
+	_, err := ParseSimModel("[", "", "", false)
+	assert.Error(t, err)
+}
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	_, e := newTestContext(t)
+	e.Register("hopper.run(?)", FuncArg{Name: "hopper.run", Params: []Param{{Name: "units", Unit: "turn", Max: 255}}},
+		Meta{Description: "run hopper", Device: "hopper", Safe: true})
+	e.Register("hopper.ready", Cond{Name: "hopper.ready"})
+	e.RegisterNewFunc("service.reset", func(context.Context) error { return nil }, Meta{Description: "reset"})
+
+	info, ok := e.Describe("hopper.run(5)")
+	require.True(t, ok)
+	assert.Equal(t, ActionInfo{
+		Name:        "hopper.run(?)",
+		Kind:        "action",
+		Description: "run hopper",
+		Device:      "hopper",
+		Params:      []Param{{Name: "units", Unit: "turn", Max: 255}},
+		Safe:        true,
+	}, info)
+	info, ok = e.Describe("hopper.ready")
+	require.True(t, ok)
+	assert.Equal(t, "condition", info.Kind)
+	assert.False(t, info.Safe, "unknown is not safe")
+	info, ok = e.Describe("ignore(1)")
+	require.True(t, ok)
+	assert.Equal(t, defaultParams, info.Params)
+	_, ok = e.Describe("typo")
+	assert.False(t, ok)
+
+	b := bytes.Buffer{}
+	require.NoError(t, e.ExportJSON(&b))
+	var list []ActionInfo
+	require.NoError(t, json.Unmarshal(b.Bytes(), &list))
+	assert.Equal(t, e.Catalog(), list)
+	assert.Contains(t, b.String(), `"unit": "turn"`)
+	assert.Equal(t, "hopper.ready", list[0].Name)
+}
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx, e := newTestContext(t)
+	result := ""
+	e.Register("log(?)", FuncArg{Name: "log", F: func(ctx context.Context, a Arg) error {
+		result += fmt.Sprintf("%d,", a)
+		return nil
+	}})
+	for _, x := range []struct{ key, scenario string }{
+		{"stock_below_min", "log(${value})"},
+		{"stock_below_min:cup", "log(${code}+1000)"},
+		{"stock_below_min:water", "log(0)"},
+		{"tele_connected", "log(7)"},
+	} {
+		d, err := e.ParseText("hook."+x.key, x.scenario)
+		require.NoError(t, err)
+		require.NoError(t, e.Hook(x.key, d))
+	}
+	assert.Error(t, e.Hook("typo", Nothing{}))
+	assert.Error(t, e.Hook("typo:cup", Nothing{}))
+
+	require.NoError(t, e.EmitSync(ctx, Event{Name: EventStockBelowMin, Subject: "cup", Vars: Vars{"code": 3, "value": 5}}))
+	assert.Equal(t, "5,1003,", result)
+	result = ""
+	require.NoError(t, e.EmitSync(ctx, Event{Name: EventTeleConnected}))
+	require.NoError(t, e.EmitSync(ctx, Event{Name: EventDeviceOffline, Subject: "evend.cup"}))
+	assert.Equal(t, "7,", result)
+
+	err := e.EmitSync(ctx, Event{Name: EventStockBelowMin, Subject: "sugar"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "variable=value not found")
+}
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+
+	h := &latencyHist{}
+	for i := 1; i <= 100; i++ {
+		h.add(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, Latency{Count: 100, P50: 50 * time.Millisecond, P95: 95 * time.Millisecond, Max: 100 * time.Millisecond}, h.summary())
+	// percentiles over recent samples, count and max over period
+	for i := 0; i < profileSamples; i++ {
+		h.add(time.Millisecond)
+	}
+	assert.Equal(t, Latency{Count: 100 + profileSamples, P50: time.Millisecond, P95: time.Millisecond, Max: 100 * time.Millisecond}, h.summary())
+
+	ctx, e := newTestContext(t)
+	e.Register("slow", Func0{Name: "slow", F: func() error { return nil }})
+	e.Register("fast", Func0{Name: "fast", F: func() error { return nil }})
+	e.SetProfile(regexp.MustCompile(`^slow`), time.Hour, nil)
+	require.NoError(t, e.Exec(ctx, e.Resolve("slow")))
+	require.NoError(t, e.Exec(ctx, e.Resolve("slow")))
+	require.NoError(t, e.Exec(ctx, e.Resolve("fast")))
+	m := e.TakeLatency()
+	assert.Len(t, m, 1)
+	assert.Equal(t, 2, m["slow"].Count)
+	assert.Empty(t, e.TakeLatency())
+}
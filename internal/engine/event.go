@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/helpers"
+)
+
+// Events emitted by drivers and subsystems, see Event.
+const (
+	EventStockBelowMin       = "stock_below_min"      // subject=stock vars: code value min
+	EventDeviceOffline       = "device_offline"       // subject=device vars: addr
+	EventDeviceRecovered     = "device_recovered"     // subject=device vars: addr
+	EventMoneyInserted       = "money_inserted"       // subject=bill|coin vars: amount credit
+	EventTransactionComplete = "transaction_complete" // subject=menu code vars: price cream sugar
+	EventTeleConnected       = "tele_connected"       // no subject, no vars
+)
+
+var knownEvents = []string{
+	EventStockBelowMin,
+	EventDeviceOffline,
+	EventDeviceRecovered,
+	EventMoneyInserted,
+	EventTransactionComplete,
+	EventTeleConnected,
+}
+
+// Event payload is available to hook scenarios as variables `${amount}`.
+type Event struct {
+	Name    string
+	Subject string
+	Vars    Vars
+}
+
+func (self Event) String() string {
+	if self.Subject == "" {
+		return self.Name
+	}
+	return self.Name + ":" + self.Subject
+}
+
+// Hook registers d to run on event.
+// key is event name or `name:subject` to match one stock, device, etc.
+func (self *Engine) Hook(key string, d Doer) error {
+	name := key
+	if i := strings.IndexByte(key, ':'); i != -1 {
+		name = key[:i]
+	}
+	known := false
+	for _, x := range knownEvents {
+		known = known || x == name
+	}
+	if !known {
+		return errors.NotValidf("hook event=%s expected one of %s", name, strings.Join(knownEvents, ","))
+	}
+	self.hooks.Lock()
+	if self.hooks.m == nil {
+		self.hooks.m = make(map[string][]Doer)
+	}
+	self.hooks.m[key] = append(self.hooks.m[key], d)
+	self.hooks.Unlock()
+	return nil
+}
+
+// Emit is Engine.Emit for code without engine at hand, no-op if ctx has no engine.
+func Emit(ctx context.Context, ev Event) {
+	if e, ok := ctx.Value(ContextKey).(*Engine); ok {
+		e.Emit(ctx, ev)
+	}
+}
+
+// Emit runs hooks of event in background, errors are logged.
+func (self *Engine) Emit(ctx context.Context, ev Event) {
+	if !self.hooked(ev) {
+		return
+	}
+	go func() {
+		if err := self.EmitSync(ctx, ev); err != nil {
+			self.Log.Error(errors.Annotatef(err, "event=%s", ev.String()))
+		}
+	}()
+}
+
+// EmitSync runs hooks of event one by one: generic `name` first, then `name:subject`.
+func (self *Engine) EmitSync(ctx context.Context, ev Event) error {
+	self.hooks.Lock()
+	list := append([]Doer(nil), self.hooks.m[ev.Name]...)
+	if ev.Subject != "" {
+		list = append(list, self.hooks.m[ev.String()]...)
+	}
+	self.hooks.Unlock()
+	if len(list) == 0 {
+		return nil
+	}
+
+	self.Log.Debugf("engine event=%s vars=%v hooks=%d", ev.String(), ev.Vars, len(list))
+	ctx = WithVars(ctx, ev.Vars)
+	errs := make([]error, 0)
+	for _, d := range list {
+		if err := self.Exec(ctx, d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return helpers.FoldErrors(errs)
+}
+
+func (self *Engine) hooked(ev Event) bool {
+	self.hooks.Lock()
+	defer self.hooks.Unlock()
+	return len(self.hooks.m[ev.Name]) != 0 || (ev.Subject != "" && len(self.hooks.m[ev.String()]) != 0)
+}
@@ -0,0 +1,196 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/internal/engine"
+)
+
+// hwPoint maps stock units to hardware units, one row of `hw_table`.
+type hwPoint struct {
+	stock float32
+	hw    float32
+}
+
+// parseHwTable validates config rows `[stock, hw]`, result is sorted by stock.
+func parseHwTable(rows [][]float32) ([]hwPoint, error) {
+	table := make([]hwPoint, 0, len(rows))
+	for i, row := range rows {
+		if len(row) != 2 {
+			return nil, errors.NotValidf("hw_table row=%d expected [stock, hw]", i)
+		}
+		if row[0] <= 0 || row[1] <= 0 {
+			return nil, errors.NotValidf("hw_table row=%d %v values must be positive", i, row)
+		}
+		table = append(table, hwPoint{stock: row[0], hw: row[1]})
+	}
+	sort.Slice(table, func(a, b int) bool { return table[a].stock < table[b].stock })
+	for i := 1; i < len(table); i++ {
+		if table[i].stock == table[i-1].stock || table[i].hw <= table[i-1].hw {
+			return nil, errors.NotValidf("hw_table %s must increase", formatHwTable(table))
+		}
+	}
+	return table, nil
+}
+
+// interpolate is piecewise linear through (0,0) and table points,
+// beyond last point the last segment is extended.
+func interpolate(table []hwPoint, x float32) float32 {
+	lo, hi := hwPoint{}, table[0]
+	for i := 1; i < len(table) && x > hi.stock; i++ {
+		lo, hi = table[i-1], table[i]
+	}
+	return lo.hw + (x-lo.stock)*(hi.hw-lo.hw)/(hi.stock-lo.stock)
+}
+
+func formatHwTable(table []hwPoint) string {
+	rows := make([]string, len(table))
+	for i, p := range table {
+		rows[i] = fmt.Sprintf("[%g, %g]", p.stock, p.hw)
+	}
+	return "[" + strings.Join(rows, ", ") + "]"
+}
+
+// Calibration procedure:
+// - `stock.{name}.calibrate(hw)` sends hw units to hardware as is, stock value is not changed
+// - measure output, `stock.{name}.measured(x)` records point [x, hw]
+// - repeat with several doses, `stock.{name}.calibration` applies and persists table, logs config line
+func (s *Stock) registerCalibrate(e *engine.Engine, doAdd engine.Doer) {
+	e.Register(fmt.Sprintf("stock.%s.calibrate(?)", s.Name), engine.FuncArg{
+		Name: fmt.Sprintf("stock.%s.calibrate", s.Name),
+		F: func(ctx context.Context, arg engine.Arg) error {
+			d, _, err := engine.ArgApply(doAdd, arg)
+			if err != nil {
+				return errors.Annotatef(err, "stock=%s calibrate", s.Name)
+			}
+			if err = engine.GetGlobal(ctx).Exec(ctx, d); err != nil {
+				return errors.Annotatef(err, "stock=%s calibrate", s.Name)
+			}
+			s.hw.Lock()
+			s.hw.lastCalibrate = float32(arg)
+			s.hw.Unlock()
+			return nil
+		}},
+		engine.Meta{Description: fmt.Sprintf("calibration: send argument to stock %s hardware as is, without hw_table", s.Name)})
+	e.Register(fmt.Sprintf("stock.%s.measured(?)", s.Name), engine.FuncArg{
+		Name: fmt.Sprintf("stock.%s.measured", s.Name),
+		F: func(ctx context.Context, arg engine.Arg) error {
+			return s.Measured(float32(arg))
+		}},
+		engine.Meta{Description: fmt.Sprintf("calibration: record measured output of last stock.%s.calibrate in stock units", s.Name)})
+	e.Register(fmt.Sprintf("stock.%s.calibration", s.Name), engine.Func{
+		Name: fmt.Sprintf("stock.%s.calibration", s.Name),
+		F: func(ctx context.Context) error {
+			line, err := s.ApplyCalibration()
+			if err != nil {
+				return err
+			}
+			engine.GetGlobal(ctx).Log.Infof("stock=%s calibration applied, config: %s", s.Name, line)
+			if s.inv != nil {
+				return errors.Annotatef(s.inv.Persist.Store(), "stock=%s calibration", s.Name)
+			}
+			return nil
+		}},
+		engine.Meta{Description: fmt.Sprintf("calibration: use recorded points as stock %s hw_table", s.Name)})
+}
+
+// Measured records calibration point for last calibrate dose.
+func (s *Stock) Measured(v float32) error {
+	s.hw.Lock()
+	defer s.hw.Unlock()
+	if s.hw.lastCalibrate == 0 {
+		return errors.Errorf("stock=%s measured without calibrate", s.Name)
+	}
+	if v <= 0 {
+		return errors.NotValidf("stock=%s measured=%g", s.Name, v)
+	}
+	p := hwPoint{stock: v, hw: s.hw.lastCalibrate}
+	for i, x := range s.hw.measured {
+		if x.hw == p.hw { // repeated dose replaces previous measurement
+			s.hw.measured[i] = p
+			return nil
+		}
+	}
+	s.hw.measured = append(s.hw.measured, p)
+	return nil
+}
+
+// ApplyCalibration replaces hw table with measured points.
+// Table is kept in inventory persistent state over config hw_table,
+// returns config line to make it permanent.
+func (s *Stock) ApplyCalibration() (string, error) {
+	s.hw.Lock()
+	defer s.hw.Unlock()
+	rows := make([][]float32, len(s.hw.measured))
+	for i, p := range s.hw.measured {
+		rows[i] = []float32{p.stock, p.hw}
+	}
+	if len(rows) == 0 {
+		return "", errors.Errorf("stock=%s no calibration points, use stock.%s.measured", s.Name, s.Name)
+	}
+	table, err := parseHwTable(rows)
+	if err != nil {
+		return "", errors.Annotatef(err, "stock=%s calibration", s.Name)
+	}
+	s.hw.table = table
+	s.hw.calibrated = true
+	s.hw.measured = nil
+	s.hw.lastCalibrate = 0
+	return fmt.Sprintf("stock %q { hw_table = %s }", s.Name, formatHwTable(table)), nil
+}
+
+// calibratedTable is flat [stock, hw...] for persistent state, nil without calibration.
+func (s *Stock) calibratedTable() []float32 {
+	s.hw.Lock()
+	defer s.hw.Unlock()
+	if !s.hw.calibrated {
+		return nil
+	}
+	flat := make([]float32, 0, len(s.hw.table)*2)
+	for _, p := range s.hw.table {
+		flat = append(flat, p.stock, p.hw)
+	}
+	return flat
+}
+
+// restoreCalibration replaces hw table with persisted calibration.
+func (s *Stock) restoreCalibration(flat []float32) error {
+	if len(flat)%2 != 0 {
+		return errors.NotValidf("stock=%s persisted hw_table len=%d", s.Name, len(flat))
+	}
+	rows := make([][]float32, 0, len(flat)/2)
+	for i := 0; i < len(flat); i += 2 {
+		rows = append(rows, flat[i:i+2])
+	}
+	table, err := parseHwTable(rows)
+	if err != nil {
+		return errors.Annotatef(err, "stock=%s persisted calibration", s.Name)
+	}
+	s.hw.Lock()
+	s.hw.table = table
+	s.hw.calibrated = true
+	s.hw.Unlock()
+	return nil
+}
+
+func (s *Stock) translateHw(arg int32) float32 {
+	if arg < 0 {
+		return -s.translateHw(-arg)
+	}
+	s.hw.Lock()
+	table, rate := s.hw.table, s.hw.rate
+	s.hw.Unlock()
+	if len(table) == 0 || arg == 0 {
+		return translate(arg, rate)
+	}
+	result := float32(math.Round(float64(interpolate(table, float32(arg)))))
+	if result <= 0 {
+		return 1
+	}
+	return result
+}
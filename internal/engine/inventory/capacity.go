@@ -0,0 +1,44 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+func (s *Stock) Capacity() float32 { return s.capacity.Load() }
+
+// SetCapacity overrides capacity from config, 0 is unknown.
+func (s *Stock) SetCapacity(v float32) { s.capacity.Store(v) }
+
+// Fill returns value in percent of capacity, false if capacity is unknown.
+func (s *Stock) Fill() (float32, bool) {
+	c := s.Capacity()
+	if c <= 0 {
+		return 0, false
+	}
+	return s.Value() / c * 100, true
+}
+
+// Refill adds amount to value, journal records it with ReasonRefill.
+func (s *Stock) Refill(source string, amount float32) error {
+	if amount <= 0 {
+		return errors.NotValidf("stock=%s refill amount=%f", s.Name, amount)
+	}
+	s.setLot(ReasonRefill, source, s.Value()+amount, time.Time{})
+	return nil
+}
+
+// RefillFull sets value to capacity, returns refill amount.
+func (s *Stock) RefillFull(source string) (float32, error) {
+	c := s.Capacity()
+	if c <= 0 {
+		return 0, errors.NotValidf("stock=%s capacity unknown, refill", s.Name)
+	}
+	amount := c - s.Value()
+	if amount <= 0 {
+		return 0, nil
+	}
+	s.setLot(ReasonRefill, source, c, time.Time{})
+	return amount, nil
+}
@@ -0,0 +1,104 @@
+package inventory
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	forecastAlpha   = 0.3     // moving average weight of last day
+	forecastMinPart = 0.25    // ignore first hour after boot if observed less
+	forecastHorizon = 14 * 24 // hours, longer forecast is unknown
+)
+
+// forecast learns consumption per local hour of day from spend events.
+type forecast struct {
+	sync.Mutex
+	rate  [24]float32 // stock units per hour, moving average over days
+	known [24]bool
+	hour  time.Time // start of current hour
+	begin time.Time // observation start in current hour, boot may be mid-hour
+	spent float32   // in current hour
+}
+
+func hourStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+func (self *forecast) add(now time.Time, v float32) {
+	self.Lock()
+	self.locked_roll(now)
+	self.spent += v
+	self.Unlock()
+}
+
+// locked_roll folds finished hours into rate, hours without spend count as zero.
+func (self *forecast) locked_roll(now time.Time) {
+	current := hourStart(now)
+	if self.hour.IsZero() {
+		self.hour, self.begin = current, now
+		return
+	}
+	for n := 0; self.hour.Before(current) && n < 24; n++ {
+		next := self.hour.Add(time.Hour)
+		part := float32(next.Sub(self.begin)) / float32(time.Hour)
+		if part >= forecastMinPart {
+			h := self.hour.Hour()
+			v := self.spent / part
+			if self.known[h] {
+				v = forecastAlpha*v + (1-forecastAlpha)*self.rate[h]
+			}
+			self.rate[h], self.known[h] = v, true
+		}
+		self.hour, self.begin, self.spent = next, next, 0
+	}
+	if self.hour.Before(current) { // gap over a day, every hour is already folded
+		self.hour, self.begin = current, current
+	}
+}
+
+// estimate returns average spend per hour and time when remaining is spent.
+// Zero time means unknown: no consumption learned yet or beyond horizon.
+func (self *forecast) estimate(now time.Time, remaining float32) (float32, time.Time) {
+	self.Lock()
+	defer self.Unlock()
+	self.locked_roll(now)
+
+	var sum float32
+	n := 0
+	for h, r := range self.rate {
+		if self.known[h] {
+			sum += r
+			n++
+		}
+	}
+	if n == 0 || sum == 0 {
+		return 0, time.Time{}
+	}
+	mean := sum / float32(n)
+	if remaining <= 0 {
+		return mean, now
+	}
+
+	t := now
+	for i := 0; i < forecastHorizon; i++ {
+		r := mean
+		if h := t.Hour(); self.known[h] {
+			r = self.rate[h]
+		}
+		next := hourStart(t).Add(time.Hour)
+		use := r * float32(next.Sub(t).Hours())
+		if use >= remaining {
+			return mean, t.Add(time.Duration(float64(remaining) / float64(r) * float64(time.Hour))).Round(time.Second)
+		}
+		remaining -= use
+		t = next
+	}
+	return mean, time.Time{}
+}
+
+// Forecast returns average spend per hour and expected time of reaching min.
+// Zero time means unknown.
+func (s *Stock) Forecast(now time.Time) (float32, time.Time) {
+	return s.forecast.estimate(now, s.Value()-s.min)
+}
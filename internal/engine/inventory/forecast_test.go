@@ -0,0 +1,48 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecast(t *testing.T) {
+	t.Parallel()
+
+	f := &forecast{}
+	start := time.Date(2020, 5, 17, 0, 0, 0, 0, time.Local)
+	rate, at := f.estimate(start, 100)
+	assert.Zero(t, rate)
+	assert.True(t, at.IsZero(), "nothing learned")
+
+	// two days of 10 units per hour in 08:00-20:00, night is idle
+	f.add(start, 0)
+	for day := 0; day < 2; day++ {
+		for h := 8; h < 20; h++ {
+			f.add(start.Add(time.Duration(day*24+h)*time.Hour+30*time.Minute), 10)
+		}
+	}
+	now := start.Add(2*24*time.Hour + 6*time.Hour)
+	cases := []struct {
+		remaining float32
+		expect    time.Time
+	}{
+		{0, now},
+		{-5, now},
+		{25, start.Add(2*24*time.Hour + 10*time.Hour + 30*time.Minute)},
+		{130, start.Add(3*24*time.Hour + 9*time.Hour)},
+		{1e6, time.Time{}},
+	}
+	for _, c := range cases {
+		rate, at := f.estimate(now, c.remaining)
+		assert.Equal(t, float32(5), rate)
+		assert.True(t, c.expect.Equal(at), "remaining=%v expect=%v at=%v", c.remaining, c.expect, at)
+	}
+
+	// first hour observed briefly is not representative
+	f = &forecast{}
+	f.add(start.Add(50*time.Minute), 10)
+	rate, _ = f.estimate(start.Add(2*time.Hour), 1)
+	assert.Zero(t, rate)
+}
@@ -13,16 +13,22 @@ import (
 )
 
 var (
-	ErrStockLow = errors.New("Stock is too low")
+	ErrStockLow     = errors.New("Stock is too low")
+	ErrStockExpired = errors.New("Stock is expired")
 )
 
 type Inventory struct {
 	persist.Persist
-	config *engine_config.Inventory
-	log    *log2.Log
-	mu     sync.RWMutex
-	byName map[string]*Stock
-	byCode map[uint32]*Stock
+	Journal Journal
+	config  *engine_config.Inventory
+	log     *log2.Log
+	mu      sync.RWMutex
+	byName  map[string]*Stock
+	byCode  map[uint32]*Stock
+	subs    struct {
+		sync.Mutex
+		list []func(*Stock)
+	}
 }
 
 func (self *Inventory) Init(ctx context.Context, c *engine_config.Inventory, engine *engine.Engine) error {
@@ -45,6 +51,7 @@ func (self *Inventory) Init(ctx context.Context, c *engine_config.Inventory, eng
 			errs = append(errs, err)
 			continue
 		}
+		stock.inv = self
 		self.byName[stock.Name] = stock
 		if first, ok := self.byCode[stock.Code]; !ok {
 			self.byCode[stock.Code] = stock
@@ -52,10 +59,38 @@ func (self *Inventory) Init(ctx context.Context, c *engine_config.Inventory, eng
 			self.log.Errorf("stock=%s duplicate code=%d first=%s", stock.Name, stock.Code, first)
 		}
 	}
+	for _, r := range c.Migrate {
+		if _, ok := self.byName[r.To]; !ok {
+			errs = append(errs, errors.NotFoundf("inventory migrate from=%s to=%s stock", r.From, r.To))
+		}
+	}
 
 	return helpers.FoldErrors(errs)
 }
 
+// Stop flushes and closes journal.
+func (self *Inventory) Stop() {
+	if err := self.Journal.Close(); err != nil && self.log != nil {
+		self.log.Error(err)
+	}
+}
+
+// Subscribe calls fun after every stock value change, fun must not block.
+func (self *Inventory) Subscribe(fun func(*Stock)) {
+	self.subs.Lock()
+	self.subs.list = append(self.subs.list, fun)
+	self.subs.Unlock()
+}
+
+func (self *Inventory) notify(s *Stock) {
+	self.subs.Lock()
+	list := self.subs.list
+	self.subs.Unlock()
+	for _, fun := range list {
+		fun(s)
+	}
+}
+
 func (self *Inventory) EnableAll()  { self.Iter(func(s *Stock) { s.Enable() }) }
 func (self *Inventory) DisableAll() { self.Iter(func(s *Stock) { s.Disable() }) }
 
@@ -0,0 +1,323 @@
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/log2"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+// Change reasons.
+const (
+	ReasonSpend  = "spend"  // scenario consumed stock
+	ReasonSet    = "set"    // value replaced
+	ReasonRefill = "refill" // value increased by technician, after-before is refill amount
+)
+
+// Change sources. Spend source is taken from context, see WithSource.
+const (
+	SourceEngine  = "engine"     // default: scenario, hook, job
+	SourceSale    = "sale"       // customer vend, "sale menu.CODE"
+	SourceService = "service"    // technician in service menu
+	SourceTele    = "tele"       // remote command
+	SourceCli     = "engine-cli" // technician in command line
+)
+
+const sourceKey = "run/inventory-source"
+
+// Journal keeps this many changes in memory for Query without file and telemetry.
+const journalMemory = 1024
+
+// Journal file is rotated to path.1 at this size, Query reads only last this many bytes of each.
+const journalMaxSize = 1 << 20
+
+// Journal writes are buffered and synced to disk periodically and on Close.
+const journalFlushInterval = 5 * time.Second
+
+// WithSource sets source of stock spends in ctx.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+func sourceFromContext(ctx context.Context) string {
+	if s, ok := ctx.Value(sourceKey).(string); ok {
+		return s
+	}
+	return SourceEngine
+}
+
+// Change is one inventory mutation, a line of journal file.
+type Change struct {
+	Time   time.Time `json:"time"`
+	Stock  string    `json:"stock"`
+	Code   uint32    `json:"code"`
+	Reason string    `json:"reason"`
+	Source string    `json:"source"`
+	Before float32   `json:"before"`
+	After  float32   `json:"after"`
+}
+
+// Journal is append-only log of inventory changes, JSON line per Change.
+// File is rotated at maxSize, one previous file is kept.
+// Without file (persist disabled) only last changes are kept in memory.
+type Journal struct {
+	mu      sync.Mutex
+	log     *log2.Log
+	path    string
+	file    *os.File
+	w       *bufio.Writer
+	size    int64
+	maxSize int64 // default journalMaxSize
+	stop    chan struct{}
+	recent  []Change
+	pending []Change // not yet sent in telemetry
+	dropped int      // pending overflow since last TakeTele
+}
+
+// Init opens journal file for append, empty path keeps journal in memory.
+func (self *Journal) Init(path string, log *log2.Log) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.log = log
+	if self.maxSize == 0 {
+		self.maxSize = journalMaxSize
+	}
+	if path == "" {
+		return nil
+	}
+	self.path = path
+	if err := self.locked_open(); err != nil {
+		return errors.Annotate(err, "inventory journal")
+	}
+	self.stop = make(chan struct{})
+	go self.flushLoop(self.stop)
+	return nil
+}
+
+// Close flushes and closes file, later changes are kept only in memory.
+func (self *Journal) Close() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.file == nil {
+		return nil
+	}
+	close(self.stop)
+	err := self.locked_flush()
+	if errClose := self.file.Close(); err == nil {
+		err = errClose
+	}
+	self.file, self.w = nil, nil
+	return errors.Annotate(err, "inventory journal")
+}
+
+// Record appends change. Write errors are logged, inventory must work without journal.
+func (self *Journal) Record(c Change) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.recent = appendLimit(self.recent, c)
+	if len(self.pending) >= journalMemory {
+		self.dropped++
+	}
+	self.pending = appendLimit(self.pending, c)
+	if self.file == nil {
+		return
+	}
+	b, err := json.Marshal(c)
+	if err == nil {
+		var n int
+		n, err = self.w.Write(append(b, '\n'))
+		self.size += int64(n)
+	}
+	if err == nil && self.size >= self.maxSize {
+		err = self.locked_rotate()
+	}
+	if err != nil {
+		self.logError(err)
+	}
+}
+
+// Flush writes buffered changes to disk.
+func (self *Journal) Flush() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return errors.Annotate(self.locked_flush(), "inventory journal")
+}
+
+// Query returns changes of stock (empty for all) since time, at most limit last ones (0 for all).
+// With file, only tail of previous and current file is read, see journalMaxSize.
+func (self *Journal) Query(stock string, since time.Time, limit int) ([]Change, error) {
+	self.mu.Lock()
+	if err := self.locked_flush(); err != nil {
+		self.logError(err)
+	}
+	path, maxSize, recent := self.path, self.maxSize, append([]Change(nil), self.recent...)
+	self.mu.Unlock()
+
+	all := recent
+	if path != "" {
+		all = nil
+		for _, p := range []string{path + ".1", path} {
+			changes, err := readTail(p, maxSize)
+			if err != nil {
+				return nil, errors.Annotate(err, "inventory journal")
+			}
+			all = append(all, changes...)
+		}
+	}
+
+	result := make([]Change, 0, len(all))
+	for _, c := range all {
+		if (stock == "" || c.Stock == stock) && !c.Time.Before(since) {
+			result = append(result, c)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+// TakeTele returns changes since last call for telemetry.
+// Changes beyond journalMemory between calls are dropped and logged.
+func (self *Journal) TakeTele() []*tele_api.Inventory_Change {
+	self.mu.Lock()
+	pending, dropped := self.pending, self.dropped
+	self.pending, self.dropped = nil, 0
+	self.mu.Unlock()
+	if dropped != 0 {
+		self.logError(errors.Errorf("telemetry dropped=%d changes over limit=%d", dropped, journalMemory))
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	result := make([]*tele_api.Inventory_Change, len(pending))
+	for i, c := range pending {
+		result[i] = &tele_api.Inventory_Change{
+			Time:   c.Time.UnixNano(),
+			Code:   c.Code,
+			Name:   c.Stock,
+			Reason: c.Reason,
+			Source: c.Source,
+			Before: c.Before,
+			After:  c.After,
+		}
+	}
+	return result
+}
+
+func (self *Journal) flushLoop(stop <-chan struct{}) {
+	tmr := time.NewTicker(journalFlushInterval)
+	defer tmr.Stop()
+	for {
+		select {
+		case <-tmr.C:
+			if err := self.Flush(); err != nil {
+				self.logError(err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (self *Journal) locked_open() error {
+	f, err := os.OpenFile(self.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	self.file, self.w, self.size = f, bufio.NewWriter(f), info.Size()
+	return nil
+}
+
+func (self *Journal) locked_flush() error {
+	if self.file == nil {
+		return nil
+	}
+	if err := self.w.Flush(); err != nil {
+		return err
+	}
+	return self.file.Sync()
+}
+
+// locked_rotate replaces previous file with current and starts new one.
+func (self *Journal) locked_rotate() error {
+	err := self.locked_flush()
+	if errClose := self.file.Close(); err == nil {
+		err = errClose
+	}
+	self.file, self.w = nil, nil
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(self.path, self.path+".1"); err != nil {
+		return err
+	}
+	return self.locked_open()
+}
+
+func (self *Journal) logError(err error) {
+	if self.log != nil {
+		self.log.Errorf("inventory journal path=%s err=%v", self.path, err)
+	}
+}
+
+// readTail parses last maxSize bytes of journal file, missing file is empty.
+func readTail(path string, maxSize int64) ([]Change, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	if offset := info.Size() - maxSize; offset > 0 {
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		// skip partial line
+		br := bufio.NewReader(f)
+		if _, err = br.ReadBytes('\n'); err != nil && err != io.EOF {
+			return nil, err
+		}
+		r = br
+	}
+	var result []Change
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var c Change
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, errors.Annotatef(err, "path=%s line=%q", path, scanner.Text())
+		}
+		result = append(result, c)
+	}
+	return result, scanner.Err()
+}
+
+func appendLimit(list []Change, c Change) []Change {
+	if len(list) >= journalMemory {
+		list = append(list[:0], list[len(list)-journalMemory+1:]...)
+	}
+	return append(list, c)
+}
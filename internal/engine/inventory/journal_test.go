@@ -0,0 +1,121 @@
+package inventory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/vender/internal/engine"
+	engine_config "github.com/temoto/vender/internal/engine/config"
+	"github.com/temoto/vender/log2"
+)
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	path := filepath.Join(t.TempDir(), "inventory-journal")
+	inv := &Inventory{}
+	j := &inv.Journal
+	require.NoError(t, j.Init(path, log))
+	s, err := NewStock(engine_config.Stock{Name: "cup", Code: 4, SpendRate: 2}, e)
+	require.NoError(t, err)
+	s.inv = inv
+	notified := 0
+	inv.Subscribe(func(*Stock) { notified++ })
+
+	begin := time.Now()
+	s.SetBy(SourceService, 10)
+	require.NoError(t, e.Exec(WithSource(ctx, "sale menu.1"), e.Resolve("stock.cup.spend1")))
+	require.NoError(t, e.Exec(ctx, e.Resolve("stock.cup.spend1")))
+	s.Set(3)
+	assert.Equal(t, 4, notified)
+
+	type row struct {
+		reason, source string
+		before, after  float32
+	}
+	expect := []row{
+		{ReasonSet, SourceService, 0, 10},
+		{ReasonSpend, "sale menu.1", 10, 8},
+		{ReasonSpend, SourceEngine, 8, 6},
+		{ReasonSet, SourceEngine, 6, 3},
+	}
+	check := func(changes []Change, expect []row) {
+		got := make([]row, len(changes))
+		for i, c := range changes {
+			assert.Equal(t, "cup", c.Stock)
+			assert.Equal(t, uint32(4), c.Code)
+			assert.False(t, c.Time.Before(begin.Truncate(time.Second)))
+			got[i] = row{c.Reason, c.Source, c.Before, c.After}
+		}
+		assert.Equal(t, expect, got)
+	}
+	changes, err := j.Query("", time.Time{}, 0)
+	require.NoError(t, err)
+	check(changes, expect)
+	changes, err = j.Query("cup", time.Time{}, 1)
+	require.NoError(t, err)
+	check(changes, expect[3:])
+	changes, err = j.Query("tea", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, changes, 0)
+
+	tele := j.TakeTele()
+	require.Len(t, tele, 4)
+	assert.Equal(t, "sale menu.1", tele[1].Source)
+	assert.Equal(t, float32(8), tele[1].After)
+	assert.Nil(t, j.TakeTele())
+
+	// journal survives restart
+	inv.Stop()
+	j2 := &Journal{}
+	require.NoError(t, j2.Init(path, log))
+	defer j2.Close()
+	changes, err = j2.Query("", time.Time{}, 0)
+	require.NoError(t, err)
+	check(changes, expect)
+}
+
+func TestJournalRotate(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	path := filepath.Join(t.TempDir(), "inventory-journal")
+	j := &Journal{maxSize: 500}
+	require.NoError(t, j.Init(path, log))
+	defer j.Close()
+
+	const n = journalMemory + 10
+	for i := 0; i < n; i++ {
+		j.Record(Change{Stock: "cup", Reason: ReasonSet, Source: SourceEngine, After: float32(i)})
+	}
+	changes, err := j.Query("", time.Time{}, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, changes)
+	assert.Less(t, len(changes), 20, "query reads bounded tail")
+	assert.Equal(t, float32(n-1), changes[len(changes)-1].After)
+	for i := 1; i < len(changes); i++ {
+		assert.Equal(t, changes[i-1].After+1, changes[i].After)
+	}
+	for _, p := range []string{path, path + ".1"} {
+		info, err := os.Stat(p)
+		require.NoError(t, err)
+		assert.Less(t, info.Size(), int64(500+200), "rotated after line crossing limit")
+	}
+
+	// telemetry keeps last journalMemory changes, overflow is counted
+	assert.Equal(t, 10, j.dropped)
+	tele := j.TakeTele()
+	require.Len(t, tele, journalMemory)
+	assert.Equal(t, float32(10), tele[0].After)
+	assert.Equal(t, 0, j.dropped)
+}
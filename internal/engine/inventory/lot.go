@@ -0,0 +1,126 @@
+package inventory
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultExpireWarn = 24 * time.Hour
+
+// Lot is one refill of perishable stock, spent first in first out.
+type Lot struct {
+	Quantity float32
+	Expire   time.Time
+}
+
+// lots cover newest part of stock value. Value not covered by lots
+// (before first refill, stock without shelf_life) never expires and is spent first.
+type lots struct {
+	sync.Mutex
+	shelfLife  time.Duration // 0 disables lots
+	expireWarn time.Duration
+	list       []Lot // oldest first
+	timer      *time.Timer
+}
+
+// locked_trim spends oldest lots until they fit in value.
+func (self *lots) locked_trim(value float32) {
+	var sum float32
+	for _, l := range self.list {
+		sum += l.Quantity
+	}
+	excess := sum - value
+	for excess > 0 && len(self.list) != 0 {
+		if first := &self.list[0]; first.Quantity > excess {
+			first.Quantity -= excess
+			break
+		}
+		excess -= self.list[0].Quantity
+		self.list = self.list[1:]
+	}
+}
+
+// locked_next returns earliest expiry, zero if there are no lots.
+func (self *lots) locked_next() time.Time {
+	var next time.Time
+	for _, l := range self.list {
+		if next.IsZero() || l.Expire.Before(next) {
+			next = l.Expire
+		}
+	}
+	return next
+}
+
+// Lots returns copy of stock lots, oldest first.
+func (s *Stock) Lots() []Lot {
+	s.lots.Lock()
+	defer s.lots.Unlock()
+	return append([]Lot(nil), s.lots.list...)
+}
+
+// Expire returns earliest lot expiry, zero if stock has no lots.
+func (s *Stock) Expire() time.Time {
+	s.lots.Lock()
+	defer s.lots.Unlock()
+	return s.lots.locked_next()
+}
+
+// Expired is true when any lot in stock is past expiry.
+func (s *Stock) Expired(now time.Time) bool {
+	next := s.Expire()
+	return !next.IsZero() && !now.Before(next)
+}
+
+// ExpireSoon is true when earliest expiry is within expire_warn.
+func (s *Stock) ExpireSoon(now time.Time) bool {
+	next := s.Expire()
+	return !next.IsZero() && now.Add(s.lots.expireWarn).After(next)
+}
+
+// SetLot replaces value, increase becomes a lot expiring at expire.
+// Zero expire means now + shelf_life, without shelf_life increase is not a lot.
+func (s *Stock) SetLot(source string, new float32, expire time.Time) {
+	s.setLot(ReasonSet, source, new, expire)
+}
+
+func (s *Stock) setLot(reason, source string, new float32, expire time.Time) {
+	old := s.value.Swap(new)
+	s.lots.Lock()
+	if new > old && (s.lots.shelfLife != 0 || !expire.IsZero()) {
+		if expire.IsZero() {
+			expire = time.Now().Add(s.lots.shelfLife)
+		}
+		s.lots.list = append(s.lots.list, Lot{Quantity: new - old, Expire: expire})
+	}
+	s.locked_lotsChanged()
+	s.lots.Unlock()
+	s.record(reason, source, old, new)
+}
+
+// restoreLots replaces lots from persistent state, not a change for journal.
+func (s *Stock) restoreLots(list []Lot) {
+	s.lots.Lock()
+	s.lots.list = list
+	s.locked_lotsChanged()
+	s.lots.Unlock()
+}
+
+func (s *Stock) spendLots() {
+	s.lots.Lock()
+	if len(s.lots.list) != 0 {
+		s.locked_lotsChanged()
+	}
+	s.lots.Unlock()
+}
+
+// locked_lotsChanged fits lots in value and notifies subscribers when next lot expires.
+func (s *Stock) locked_lotsChanged() {
+	s.lots.locked_trim(s.value.Load())
+	if s.lots.timer != nil {
+		s.lots.timer.Stop()
+		s.lots.timer = nil
+	}
+	if next := s.lots.locked_next(); !next.IsZero() {
+		s.lots.timer = time.AfterFunc(time.Until(next), s.notify)
+	}
+}
@@ -0,0 +1,91 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/vender/internal/engine"
+	engine_config "github.com/temoto/vender/internal/engine/config"
+	"github.com/temoto/vender/log2"
+)
+
+func TestStockLots(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	config := &engine_config.Inventory{Stocks: []engine_config.Stock{
+		{Name: "milk", Check: true, RegisterAdd: "ignore(?)", ShelfLife: "72h"},
+	}}
+	inv := &Inventory{}
+	require.NoError(t, inv.Init(ctx, config, e))
+	s := inv.MustGet(t, "milk")
+	type lot struct {
+		q float32
+		t time.Time
+	}
+	check := func(expect ...lot) {
+		t.Helper()
+		lots := s.Lots()
+		require.Len(t, lots, len(expect))
+		for i, l := range lots {
+			assert.Equal(t, expect[i].q, l.Quantity)
+			assert.WithinDuration(t, expect[i].t, l.Expire, time.Second)
+		}
+	}
+
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	s.value.Store(2) // persisted before shelf_life was configured, no expiry, spent first
+	s.SetBy(SourceService, 7)
+	s.SetLot(SourceTele, 10, soon)
+	check(lot{5, now.Add(72 * time.Hour)}, lot{3, soon})
+	assert.Equal(t, soon, s.Expire())
+	assert.True(t, s.ExpireSoon(now))
+	assert.False(t, s.Expired(now))
+	assert.True(t, s.Expired(soon))
+
+	require.NoError(t, e.Exec(ctx, e.Resolve("add.milk(4)")))
+	check(lot{3, now.Add(72 * time.Hour)}, lot{3, soon})
+	s.Set(4)
+	check(lot{1, now.Add(72 * time.Hour)}, lot{3, soon})
+	assert.NoError(t, e.Resolve("add.milk(1)").Validate())
+
+	// state survives restart
+	b, err := inv.MarshalBinary()
+	require.NoError(t, err)
+	inv2 := &Inventory{}
+	require.NoError(t, inv2.Init(ctx, config, engine.NewEngine(log)))
+	require.NoError(t, inv2.UnmarshalBinary(b))
+	lots2 := inv2.MustGet(t, "milk").Lots()
+	require.Len(t, lots2, 2)
+	for i, l := range s.Lots() {
+		assert.Equal(t, l.Quantity, lots2[i].Quantity)
+		assert.True(t, l.Expire.Equal(lots2[i].Expire))
+	}
+
+	// subscribers learn about expiry without stock change
+	notified := make(chan struct{}, 1)
+	s.SetLot(SourceTele, 5, time.Now().Add(10*time.Millisecond))
+	inv.Subscribe(func(*Stock) {
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+	})
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("expiry not notified")
+	}
+	assert.Equal(t, ErrStockExpired, e.Resolve("add.milk(1)").Validate())
+	s.Set(0)
+	assert.Empty(t, s.Lots())
+	assert.True(t, s.Expire().IsZero())
+}
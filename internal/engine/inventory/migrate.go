@@ -0,0 +1,103 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stateVersion of persisted State: 0 stocks by name, 1 adds code as stable id.
+const stateVersion = 1
+
+type migrateTarget struct {
+	stock *Stock
+	from  []*State_Stock
+}
+
+// locked_migrate maps persisted stocks to configured ones. Order of matching:
+// config `migrate "from" { to = "..." }` rule, same name, same unique non-zero code.
+// Several persisted stocks mapped to one are merged. Returns what it did, for log.
+func (self *Inventory) locked_migrate(state *State) ([]migrateTarget, []string) {
+	report := make([]string, 0)
+	if state.Version != stateVersion {
+		report = append(report, fmt.Sprintf("state version=%d current=%d", state.Version, stateVersion))
+	}
+	rules := make(map[string]string, len(self.config.Migrate))
+	for _, r := range self.config.Migrate {
+		rules[r.From] = r.To
+	}
+	codeCount := make(map[uint32]int, len(self.byName))
+	for _, s := range self.byName {
+		codeCount[s.Code]++
+	}
+
+	targets := make([]migrateTarget, 0, len(state.Stocks))
+	index := make(map[*Stock]int, len(state.Stocks))
+	for _, ss := range state.Stocks {
+		var stock *Stock
+		if to, ok := rules[ss.Name]; ok {
+			stock = self.byName[to]
+			report = append(report, fmt.Sprintf("stock=%s -> %s by rule value=%g", ss.Name, to, ss.Value))
+		} else if s, ok := self.byName[ss.Name]; ok {
+			stock = s
+		} else if s, ok := self.byCode[ss.Code]; ok && ss.Code != 0 && codeCount[ss.Code] == 1 {
+			stock = s
+			report = append(report, fmt.Sprintf("stock=%s -> %s by code=%d value=%g", ss.Name, s.Name, ss.Code, ss.Value))
+		}
+		if stock == nil {
+			report = append(report, fmt.Sprintf("stock=%s code=%d value=%g dropped, not in config, add migrate rule to keep", ss.Name, ss.Code, ss.Value))
+			continue
+		}
+		if i, ok := index[stock]; ok {
+			targets[i].from = append(targets[i].from, ss)
+			continue
+		}
+		index[stock] = len(targets)
+		targets = append(targets, migrateTarget{stock: stock, from: []*State_Stock{ss}})
+	}
+
+	for _, t := range targets {
+		if len(t.from) > 1 {
+			names := make([]string, len(t.from))
+			var sum float32
+			for i, ss := range t.from {
+				names[i] = ss.Name
+				sum += ss.Value
+			}
+			report = append(report, fmt.Sprintf("stock=%s merged from %s value=%g", t.stock.Name, strings.Join(names, ","), sum))
+		}
+	}
+	return targets, report
+}
+
+// restore is not a change for journal.
+func (s *Stock) restore(from []*State_Stock) {
+	enabled := false
+	var value, capacity float32
+	lots := make([]Lot, 0)
+	for _, ss := range from {
+		enabled = enabled || ss.Enabled
+		value += ss.Value
+		if capacity == 0 {
+			capacity = ss.Capacity
+		}
+		for _, l := range ss.Lots {
+			lots = append(lots, Lot{Quantity: l.Quantity, Expire: time.Unix(0, l.Expire)})
+		}
+	}
+	if len(from) > 1 {
+		sort.SliceStable(lots, func(a, b int) bool { return lots[a].Expire.Before(lots[b].Expire) })
+	}
+
+	if enabled {
+		s.Enable()
+	} else {
+		s.Disable()
+	}
+	s.value.Store(value)
+	s.restoreLots(lots)
+	if s.Capacity() == 0 {
+		s.SetCapacity(capacity)
+	}
+}
@@ -0,0 +1,82 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/vender/internal/engine"
+	engine_config "github.com/temoto/vender/internal/engine/config"
+	"github.com/temoto/vender/log2"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	var logbuf bytes.Buffer
+	log := log2.NewWriter(&logbuf, log2.LInfo)
+	log.SetFlags(0)
+	ctx := context.WithValue(context.Background(), log2.ContextKey, log)
+	config := &engine_config.Inventory{
+		Stocks: []_CS{
+			{Name: "cup", Code: 1},
+			{Name: "sugar", Code: 2},
+			{Name: "milk", Code: 3},
+			{Name: "tea", Code: 5},
+		},
+		Migrate: []engine_config.StockMigrate{
+			{From: "sugar_old", To: "sugar"},
+			{From: "milk_a", To: "milk"},
+			{From: "milk_b", To: "milk"},
+		},
+	}
+	inv := &Inventory{}
+	require.NoError(t, inv.Init(ctx, config, engine.NewEngine(log)))
+	b, err := proto.Marshal(&State{Version: 0, Stocks: []*State_Stock{
+		{Name: "cup", Enabled: true, Value: 10},
+		{Name: "sugar_old", Enabled: true, Value: 20},
+		{Name: "milk_a", Enabled: true, Value: 3},
+		{Name: "milk_b", Value: 4},
+		{Name: "black_tea", Enabled: true, Value: 5, Code: 5},
+		{Name: "gone", Value: 6},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, inv.UnmarshalBinary(b))
+
+	expect := map[string]float32{"cup": 10, "sugar": 20, "milk": 7, "tea": 5}
+	for name, value := range expect {
+		s := inv.MustGet(t, name)
+		assert.Equal(t, value, s.Value(), name)
+		assert.True(t, s.Enabled(), name)
+	}
+	assert.Equal(t, []string{
+		"inventory migrate state version=0 current=1",
+		"inventory migrate stock=sugar_old -> sugar by rule value=20",
+		"inventory migrate stock=milk_a -> milk by rule value=3",
+		"inventory migrate stock=milk_b -> milk by rule value=4",
+		"inventory migrate stock=black_tea -> tea by code=5 value=5",
+		"inventory migrate stock=gone code=0 value=6 dropped, not in config, add migrate rule to keep",
+		"inventory migrate stock=milk merged from milk_a,milk_b value=7",
+	}, strings.Split(strings.TrimSpace(logbuf.String()), "\n"))
+
+	// current version round trip is silent
+	b, err = inv.MarshalBinary()
+	require.NoError(t, err)
+	logbuf.Reset()
+	inv2 := &Inventory{}
+	require.NoError(t, inv2.Init(ctx, config, engine.NewEngine(log)))
+	require.NoError(t, inv2.UnmarshalBinary(b))
+	assert.Empty(t, logbuf.String())
+	for name, value := range expect {
+		assert.Equal(t, value, inv2.MustGet(t, name).Value(), name)
+	}
+
+	config.Migrate = append(config.Migrate, engine_config.StockMigrate{From: "a", To: "typo"})
+	err = (&Inventory{}).Init(ctx, config, engine.NewEngine(log))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inventory migrate from=a to=typo stock not found")
+}
@@ -15,14 +15,20 @@ func (self *Inventory) UnmarshalBinary(b []byte) error {
 	}
 	self.mu.Lock()
 	defer self.mu.Unlock()
-	for _, stockState := range state.Stocks {
-		if stock, ok := self.locked_get(0, stockState.Name); ok {
-			if stockState.Enabled {
-				stock.Enable()
-			} else {
-				stock.Disable()
+	targets, report := self.locked_migrate(&state)
+	for _, line := range report {
+		self.log.Infof("inventory migrate %s", line)
+	}
+	for _, t := range targets {
+		t.stock.restore(t.from)
+		for _, ss := range t.from {
+			if len(ss.HwTable) == 0 {
+				continue
+			}
+			if err := t.stock.restoreCalibration(ss.HwTable); err != nil {
+				self.log.Error(err)
 			}
-			stock.Set(stockState.Value)
+			break
 		}
 	}
 	return nil
@@ -31,13 +37,22 @@ func (self *Inventory) UnmarshalBinary(b []byte) error {
 func (self *Inventory) MarshalBinary() ([]byte, error) {
 	self.mu.RLock()
 	defer self.mu.RUnlock()
-	state := State{Stocks: make([]*State_Stock, 0, len(self.byName))}
+	state := State{Version: stateVersion, Stocks: make([]*State_Stock, 0, len(self.byName))}
 	for _, stock := range self.byName {
-		state.Stocks = append(state.Stocks, &State_Stock{
-			Name:    stock.Name,
-			Enabled: stock.Enabled(),
-			Value:   stock.Value(),
-		})
+		lots := stock.Lots()
+		ss := &State_Stock{
+			Name:     stock.Name,
+			Code:     stock.Code,
+			Enabled:  stock.Enabled(),
+			Value:    stock.Value(),
+			Lots:     make([]*State_Stock_Lot, len(lots)),
+			Capacity: stock.Capacity(),
+			HwTable:  stock.calibratedTable(),
+		}
+		for i, l := range lots {
+			ss.Lots[i] = &State_Stock_Lot{Quantity: l.Quantity, Expire: l.Expire.UnixNano()}
+		}
+		state.Stocks = append(state.Stocks, ss)
 	}
 	return proto.Marshal(&state)
 }
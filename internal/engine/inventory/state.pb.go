@@ -20,6 +20,7 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type State struct {
 	Stocks               []*State_Stock `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	Version              uint32         `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -29,7 +30,7 @@ func (m *State) Reset()         { *m = State{} }
 func (m *State) String() string { return proto.CompactTextString(m) }
 func (*State) ProtoMessage()    {}
 func (*State) Descriptor() ([]byte, []int) {
-	return fileDescriptor_state_23037db7bb8fe8db, []int{0}
+	return fileDescriptor_state_3b8c6b6faf70f234, []int{0}
 }
 func (m *State) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_State.Unmarshal(m, b)
@@ -56,20 +57,31 @@ func (m *State) GetStocks() []*State_Stock {
 	return nil
 }
 
+func (m *State) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type State_Stock struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Enabled              bool     `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	Value                float32  `protobuf:"fixed32,3,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Name                 string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled              bool               `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Value                float32            `protobuf:"fixed32,3,opt,name=value,proto3" json:"value,omitempty"`
+	Lots                 []*State_Stock_Lot `protobuf:"bytes,4,rep,name=lots,proto3" json:"lots,omitempty"`
+	Capacity             float32            `protobuf:"fixed32,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Code                 uint32             `protobuf:"varint,6,opt,name=code,proto3" json:"code,omitempty"`
+	HwTable              []float32          `protobuf:"fixed32,7,rep,packed,name=hw_table,json=hwTable,proto3" json:"hw_table,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *State_Stock) Reset()         { *m = State_Stock{} }
 func (m *State_Stock) String() string { return proto.CompactTextString(m) }
 func (*State_Stock) ProtoMessage()    {}
 func (*State_Stock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_state_23037db7bb8fe8db, []int{0, 0}
+	return fileDescriptor_state_3b8c6b6faf70f234, []int{0, 0}
 }
 func (m *State_Stock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_State_Stock.Unmarshal(m, b)
@@ -110,23 +122,105 @@ func (m *State_Stock) GetValue() float32 {
 	return 0
 }
 
+func (m *State_Stock) GetLots() []*State_Stock_Lot {
+	if m != nil {
+		return m.Lots
+	}
+	return nil
+}
+
+func (m *State_Stock) GetCapacity() float32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *State_Stock) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *State_Stock) GetHwTable() []float32 {
+	if m != nil {
+		return m.HwTable
+	}
+	return nil
+}
+
+type State_Stock_Lot struct {
+	Quantity             float32  `protobuf:"fixed32,1,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Expire               int64    `protobuf:"varint,2,opt,name=expire,proto3" json:"expire,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *State_Stock_Lot) Reset()         { *m = State_Stock_Lot{} }
+func (m *State_Stock_Lot) String() string { return proto.CompactTextString(m) }
+func (*State_Stock_Lot) ProtoMessage()    {}
+func (*State_Stock_Lot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_state_3b8c6b6faf70f234, []int{0, 0, 0}
+}
+func (m *State_Stock_Lot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_State_Stock_Lot.Unmarshal(m, b)
+}
+func (m *State_Stock_Lot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_State_Stock_Lot.Marshal(b, m, deterministic)
+}
+func (dst *State_Stock_Lot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_State_Stock_Lot.Merge(dst, src)
+}
+func (m *State_Stock_Lot) XXX_Size() int {
+	return xxx_messageInfo_State_Stock_Lot.Size(m)
+}
+func (m *State_Stock_Lot) XXX_DiscardUnknown() {
+	xxx_messageInfo_State_Stock_Lot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_State_Stock_Lot proto.InternalMessageInfo
+
+func (m *State_Stock_Lot) GetQuantity() float32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *State_Stock_Lot) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*State)(nil), "inventory.State")
 	proto.RegisterType((*State_Stock)(nil), "inventory.State.Stock")
-}
-
-func init() { proto.RegisterFile("state.proto", fileDescriptor_state_23037db7bb8fe8db) }
-
-var fileDescriptor_state_23037db7bb8fe8db = []byte{
-	// 153 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2e, 0x2e, 0x49, 0x2c,
-	0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0xcc, 0xcc, 0x2b, 0x4b, 0xcd, 0x2b, 0xc9,
-	0x2f, 0xaa, 0x54, 0x6a, 0x61, 0xe4, 0x62, 0x0d, 0x06, 0x49, 0x09, 0xe9, 0x71, 0xb1, 0x15, 0x97,
-	0xe4, 0x27, 0x67, 0x17, 0x4b, 0x30, 0x2a, 0x30, 0x6b, 0x70, 0x1b, 0x89, 0xe9, 0xc1, 0x55, 0xe9,
-	0x81, 0x55, 0xe8, 0x05, 0x83, 0xa4, 0x83, 0xa0, 0xaa, 0xa4, 0xbc, 0x41, 0x1a, 0xf3, 0x93, 0xb3,
-	0x85, 0x84, 0xb8, 0x58, 0xf2, 0x12, 0x73, 0x53, 0x25, 0x18, 0x15, 0x18, 0x35, 0x38, 0x83, 0xc0,
-	0x6c, 0x21, 0x09, 0x2e, 0xf6, 0xd4, 0xbc, 0xc4, 0xa4, 0x9c, 0xd4, 0x14, 0x09, 0x26, 0x05, 0x46,
-	0x0d, 0x8e, 0x20, 0x18, 0x57, 0x48, 0x84, 0x8b, 0xb5, 0x2c, 0x31, 0xa7, 0x34, 0x55, 0x82, 0x59,
-	0x81, 0x51, 0x83, 0x29, 0x08, 0xc2, 0x49, 0x62, 0x03, 0x3b, 0xcc, 0x18, 0x10, 0x00, 0x00, 0xff,
-	0xff, 0x55, 0xab, 0xda, 0x72, 0xa7, 0x00, 0x00, 0x00,
+	proto.RegisterType((*State_Stock_Lot)(nil), "inventory.State.Stock.Lot")
+}
+
+func init() { proto.RegisterFile("state.proto", fileDescriptor_state_3b8c6b6faf70f234) }
+
+var fileDescriptor_state_3b8c6b6faf70f234 = []byte{
+	// 258 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xbf, 0x4e, 0xc3, 0x30,
+	0x10, 0xc6, 0xe5, 0xa4, 0xf9, 0xd3, 0xab, 0x58, 0x2c, 0x54, 0x99, 0x4c, 0x11, 0x53, 0x26, 0x0f,
+	0x30, 0xf1, 0x0e, 0x9d, 0x5c, 0x76, 0xe4, 0xa6, 0x96, 0x1a, 0x35, 0xf8, 0x42, 0x7c, 0x4d, 0xe9,
+	0xc8, 0x43, 0xf2, 0x3e, 0xc8, 0xd7, 0xa6, 0x13, 0xdb, 0xfd, 0x74, 0xdf, 0x7d, 0xfa, 0xd9, 0xb0,
+	0x0a, 0x64, 0xc9, 0xe9, 0x61, 0x44, 0x42, 0xb9, 0xec, 0xfc, 0xe4, 0x3c, 0xe1, 0x78, 0x79, 0xfe,
+	0x4d, 0x20, 0xdb, 0xc6, 0x95, 0xd4, 0x90, 0x07, 0xc2, 0xf6, 0x18, 0x94, 0xa8, 0xd3, 0x66, 0xf5,
+	0xb2, 0xd6, 0xf7, 0x94, 0xe6, 0x84, 0xde, 0xc6, 0xb5, 0xb9, 0xa5, 0xa4, 0x82, 0x62, 0x72, 0x63,
+	0xe8, 0xd0, 0xab, 0xa4, 0x16, 0xcd, 0x83, 0x99, 0xb1, 0xfa, 0xe1, 0x4e, 0x6c, 0x8f, 0x52, 0xc2,
+	0xc2, 0xdb, 0x4f, 0xa7, 0x44, 0x2d, 0x9a, 0xa5, 0xe1, 0x39, 0xde, 0x39, 0x6f, 0x77, 0xbd, 0xdb,
+	0xf3, 0x5d, 0x69, 0x66, 0x94, 0x8f, 0x90, 0x4d, 0xb6, 0x3f, 0x39, 0x95, 0xd6, 0xa2, 0x49, 0xcc,
+	0x15, 0xa4, 0x86, 0x45, 0x8f, 0x14, 0xd4, 0x82, 0xad, 0xaa, 0xff, 0xad, 0xf4, 0x06, 0xc9, 0x70,
+	0x4e, 0x56, 0x50, 0xb6, 0x76, 0xb0, 0x6d, 0x47, 0x17, 0x95, 0x71, 0xd1, 0x9d, 0xa3, 0x4f, 0x8b,
+	0x7b, 0xa7, 0x72, 0x16, 0xe6, 0x59, 0x3e, 0x41, 0x79, 0x38, 0x7f, 0x50, 0x54, 0x50, 0x45, 0x9d,
+	0x36, 0x89, 0x29, 0x0e, 0xe7, 0xf7, 0x88, 0xd5, 0x1b, 0xa4, 0x1b, 0xa4, 0xd8, 0xf8, 0x75, 0xb2,
+	0x9e, 0x62, 0xa3, 0xb8, 0x36, 0xce, 0x2c, 0xd7, 0x90, 0xbb, 0xef, 0xa1, 0x1b, 0x1d, 0x3f, 0x26,
+	0x35, 0x37, 0xda, 0xe5, 0xfc, 0xd3, 0xaf, 0x7f, 0x03, 0x00, 0x73, 0x59, 0x6d, 0xc3, 0x78, 0x01,
+	0x00, 0x00,
 }
@@ -6,6 +6,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/temoto/vender/helpers/atomic_float"
@@ -20,11 +21,22 @@ type Stock struct { //nolint:maligned
 	Name      string
 	enabled   uint32 // atomic
 	check     bool
-	hwRate    float32 // TODO table // FIXME concurrency
 	spendRate float32
 	min       float32
 	value     atomic_float.F32
+	capacity  atomic_float.F32
 	tuneKey   string
+	hw        struct {
+		sync.Mutex
+		rate          float32
+		table         []hwPoint // overrides rate
+		calibrated    bool      // table is from calibration, persisted
+		measured      []hwPoint // calibration in progress
+		lastCalibrate float32
+	}
+	forecast forecast
+	lots     lots
+	inv      *Inventory // nil if stock is not in Inventory
 
 	_copy_guard sync.Mutex //nolint:unused
 }
@@ -42,20 +54,43 @@ func NewStock(c engine_config.Stock, e *engine.Engine) (*Stock, error) {
 	if c.SpendRate == 0 {
 		c.SpendRate = 1
 	}
+	if c.Capacity < 0 {
+		return nil, errors.Errorf("stock=%s invalid capacity=%f", c.Name, c.Capacity)
+	}
 	// log.Printf("stock=%s hwRate=%f spendRate=%f", c.Name, c.HwRate, c.SpendRate)
+	hwTable, err := parseHwTable(c.HwTable)
+	if err != nil {
+		return nil, errors.Annotatef(err, "stock=%s", c.Name)
+	}
 
 	s := &Stock{
 		Name:      c.Name,
 		Code:      uint32(c.Code),
 		check:     c.Check,
 		enabled:   1,
-		hwRate:    c.HwRate,
 		spendRate: c.SpendRate,
 		min:       c.Min,
 		tuneKey:   fmt.Sprintf(tuneKeyFormat, c.Name),
 	}
+	s.capacity.Store(c.Capacity)
+	s.hw.rate = c.HwRate
+	s.hw.table = hwTable
+	s.lots.expireWarn = defaultExpireWarn
+	if c.ShelfLife != "" {
+		if s.lots.shelfLife, err = time.ParseDuration(c.ShelfLife); err != nil {
+			return nil, errors.Annotatef(err, "stock=%s shelf_life", c.Name)
+		}
+		if s.lots.shelfLife <= 0 {
+			return nil, errors.NotValidf("stock=%s shelf_life=%s", c.Name, c.ShelfLife)
+		}
+	}
+	if c.ExpireWarn != "" {
+		if s.lots.expireWarn, err = time.ParseDuration(c.ExpireWarn); err != nil {
+			return nil, errors.Annotatef(err, "stock=%s expire_warn", c.Name)
+		}
+	}
 
-	doSpend1 := engine.Func0{
+	doSpend1 := engine.Func{
 		Name: fmt.Sprintf("stock.%s.spend1", s.Name),
 		F:    s.spend1,
 	}
@@ -63,6 +98,10 @@ func NewStock(c engine_config.Stock, e *engine.Engine) (*Stock, error) {
 		Name: fmt.Sprintf("stock.%s.spend(?)", s.Name),
 		F:    s.spendArg,
 	}
+	doHas := engine.CondArg{
+		Name: fmt.Sprintf("stock.%s.has(?)", s.Name),
+		F:    s.hasArg,
+	}
 	addName := fmt.Sprintf("add.%s(?)", s.Name)
 	if c.RegisterAdd != "" {
 		doAdd, err := e.ParseText(addName, c.RegisterAdd)
@@ -75,70 +114,127 @@ func NewStock(c engine_config.Stock, e *engine.Engine) (*Stock, error) {
 			return nil, errors.Errorf("stock=%s register_add=%s no free argument", s.Name, c.RegisterAdd)
 
 		case (err == nil && ok) || engine.IsNotResolved(err): // success path
-			e.Register(addName, s.Wrap(doAdd))
+			e.Register(addName, s.Wrap(doAdd), engine.Meta{
+				Description: fmt.Sprintf("dispense stock %s, argument in stock units, hardware gets it * hw_rate or hw_table", s.Name),
+				Safe:        true,
+			})
+			s.registerCalibrate(e, doAdd)
 
 		case err != nil:
 			return nil, errors.Annotatef(err, "stock=%s register_add=%s", s.Name, c.RegisterAdd)
 		}
 	}
-	e.Register(doSpend1.Name, doSpend1)
-	e.Register(doSpendArg.Name, doSpendArg)
+	e.Register(doSpend1.Name, doSpend1,
+		engine.Meta{Description: fmt.Sprintf("subtract 1 * spend_rate from stock %s", s.Name), Safe: true})
+	e.Register(doSpendArg.Name, doSpendArg,
+		engine.Meta{Description: fmt.Sprintf("subtract argument * spend_rate from stock %s", s.Name), Safe: true})
+	e.Register(doHas.Name, doHas,
+		engine.Meta{Description: fmt.Sprintf("stock %s has argument * spend_rate above min", s.Name), Safe: true})
 
 	return s, nil
 }
 
-func (s *Stock) Enable()  { atomic.StoreUint32(&s.enabled, 1) }
-func (s *Stock) Disable() { atomic.StoreUint32(&s.enabled, 0) }
+func (s *Stock) Enable()  { atomic.StoreUint32(&s.enabled, 1); s.notify() }
+func (s *Stock) Disable() { atomic.StoreUint32(&s.enabled, 0); s.notify() }
 
 func (s *Stock) Enabled() bool { return atomic.LoadUint32(&s.enabled) == 1 }
 
 func (s *Stock) Value() float32     { return s.value.Load() }
-func (s *Stock) Set(new float32)    { s.value.Store(new) }
+func (s *Stock) Set(new float32)    { s.SetBy(SourceEngine, new) }
 func (s *Stock) Has(v float32) bool { return s.value.Load()-v >= s.min }
 func (s *Stock) String() string {
 	return fmt.Sprintf("source(name=%s value=%f)", s.Name, s.Value())
 }
 
+// SetBy replaces value, change is recorded in journal with source.
+// Increase of stock with shelf_life is a refill, see SetLot.
+func (s *Stock) SetBy(source string, new float32) { s.SetLot(source, new, time.Time{}) }
+
+func (s *Stock) record(reason, source string, before, after float32) {
+	if s.inv != nil {
+		s.inv.Journal.Record(Change{
+			Time:   time.Now(),
+			Stock:  s.Name,
+			Code:   s.Code,
+			Reason: reason,
+			Source: source,
+			Before: before,
+			After:  after,
+		})
+		s.inv.notify(s)
+	}
+}
+
+func (s *Stock) notify() {
+	if s.inv != nil {
+		s.inv.notify(s)
+	}
+}
+
 func (s *Stock) Wrap(d engine.Doer) engine.Doer {
 	return &custom{stock: s, before: d}
 }
 
-func (s *Stock) TranslateHw(arg engine.Arg) float32    { return translate(int32(arg), s.hwRate) }
+func (s *Stock) TranslateHw(arg engine.Arg) float32    { return s.translateHw(int32(arg)) }
 func (s *Stock) TranslateSpend(arg engine.Arg) float32 { return translate(int32(arg), s.spendRate) }
 
-// signature match engine.Func0.F
-func (s *Stock) spend1() error {
-	s.spendValue(s.TranslateSpend(1))
-	return nil
+// signature match engine.Func.F
+func (s *Stock) spend1(ctx context.Context) error {
+	return s.spendArg(ctx, 1)
 }
 
 // signature match engine.FuncArg.F
 func (s *Stock) spendArg(ctx context.Context, arg engine.Arg) error {
-	s.spendValue(s.TranslateSpend(arg))
+	engine.SimulationSpend(ctx, s.Name, s.TranslateSpend(arg), s.TranslateHw(arg))
+	s.spendValue(ctx, s.TranslateSpend(arg))
 	return nil
 }
 
-func (s *Stock) spendValue(v float32) {
+// signature match engine.CondArg.F
+// Disabled stock is not tracked, so it has anything.
+func (s *Stock) hasArg(ctx context.Context, arg engine.Arg) (bool, error) {
+	return !s.Enabled() || s.Has(s.TranslateSpend(arg)), nil
+}
+
+// Crossing min emits EventStockBelowMin.
+func (s *Stock) spendValue(ctx context.Context, v float32) {
 	if s.Enabled() {
-		s.value.Add(-v)
+		s.forecast.add(time.Now(), v)
+		new := s.value.Add(-v)
+		s.spendLots()
+		s.record(ReasonSpend, sourceFromContext(ctx), new+v, new)
 		// log.Printf("stock=%s value=%f", s.Name, s.Value())
+		if new < s.min && new+v >= s.min {
+			engine.Emit(ctx, engine.Event{
+				Name:    engine.EventStockBelowMin,
+				Subject: s.Name,
+				Vars:    engine.Vars{"code": engine.Arg(s.Code), "value": engine.Arg(new), "min": engine.Arg(s.min)},
+			})
+		}
 	}
 }
 
+// First positional argument is stock amount, others are passed to hardware as is.
 type custom struct {
 	stock  *Stock
 	before engine.Doer
 	after  engine.Doer
 	arg    engine.Arg
+	rest   engine.Args
 	spend  float32
 }
 
-func (c *custom) Apply(arg engine.Arg) (engine.Doer, bool, error) {
+func (c *custom) Apply(args engine.Args) (engine.Doer, bool, error) {
 	if c.after != nil {
 		err := engine.ErrArgOverwrite
 		return nil, false, errors.Annotatef(err, engine.FmtErrContext, c.stock.String())
 	}
-	return c.apply(arg)
+	if len(args.Pos) == 0 {
+		err := errors.NotValidf("stock amount argument missing")
+		return nil, false, errors.Annotatef(err, engine.FmtErrContext, c.stock.String())
+	}
+	rest := engine.Args{Pos: args.Pos[1:], Named: args.Named}
+	return c.apply(args.Pos[0], rest)
 }
 
 func (c *custom) Validate() error {
@@ -148,6 +244,9 @@ func (c *custom) Validate() error {
 	if !c.stock.Enabled() {
 		return nil
 	}
+	if c.stock.Expired(time.Now()) {
+		return ErrStockExpired
+	}
 	if !c.stock.check {
 		return nil
 	}
@@ -161,7 +260,7 @@ func (c *custom) Do(ctx context.Context) error {
 	e := engine.GetGlobal(ctx)
 	if tunedCtx, tuneRate, ok := takeTuneRate(ctx, c.stock.tuneKey); ok {
 		tunedArg := engine.Arg(math.Round(float64(c.arg) * float64(tuneRate)))
-		d, _, err := c.apply(tunedArg)
+		d, _, err := c.apply(tunedArg, c.rest)
 		// log.Printf("stock=%s before=%#v arg=%v tuneRate=%v tunedArg=%v d=%v err=%v", c.stock.String(), c.before, c.arg, tuneRate, tunedArg, d, err)
 		if err != nil {
 			return errors.Annotatef(err, "stock=%s tunedArg=%v", c.stock.Name, tunedArg)
@@ -171,7 +270,7 @@ func (c *custom) Do(ctx context.Context) error {
 
 	// log.Printf("stock=%s value=%f arg=%v spending=%f", c.stock.Name, c.stock.Value(), c.arg, c.spend)
 	// TODO remove this redundant check when sure that Validate() is called in all proper places
-	if c.stock.check && !c.stock.Has(c.spend) {
+	if c.stock.Enabled() && c.stock.check && !c.stock.Has(c.spend) {
 		return errors.Errorf("stock=%s check fail", c.stock.Name)
 	}
 
@@ -182,7 +281,8 @@ func (c *custom) Do(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	c.stock.spendValue(c.spend)
+	engine.SimulationSpend(ctx, c.stock.Name, c.spend, c.stock.TranslateHw(c.arg))
+	c.stock.spendValue(ctx, c.spend)
 	return nil
 }
 
@@ -190,9 +290,19 @@ func (c *custom) String() string {
 	return fmt.Sprintf("stock.%s(%d)", c.stock.Name, c.arg)
 }
 
-func (c *custom) apply(arg engine.Arg) (engine.Doer, bool, error) {
-	hwArg := engine.Arg(c.stock.TranslateHw(arg))
-	after, applied, err := engine.ArgApply(c.before, hwArg)
+func (c *custom) Children() []engine.Doer {
+	if c.after != nil {
+		return []engine.Doer{c.after}
+	}
+	return []engine.Doer{c.before}
+}
+
+func (c *custom) apply(arg engine.Arg, rest engine.Args) (engine.Doer, bool, error) {
+	hwArgs := engine.Args{
+		Pos:   append([]engine.Arg{engine.Arg(c.stock.TranslateHw(arg))}, rest.Pos...),
+		Named: rest.Named,
+	}
+	after, applied, err := engine.ArgsApply(c.before, hwArgs)
 	if err != nil {
 		return nil, false, errors.Annotatef(err, engine.FmtErrContext, c.stock.String())
 	}
@@ -205,11 +315,16 @@ func (c *custom) apply(arg engine.Arg) (engine.Doer, bool, error) {
 		before: c.before,
 		after:  after,
 		arg:    arg,
+		rest:   rest,
 		spend:  c.stock.TranslateSpend(arg),
 	}
 	return new, true, nil
 }
 
+// compile-time interface checks
+var _ engine.ArgApplier = &custom{}
+var _ engine.Composite = &custom{}
+
 func takeTuneRate(ctx context.Context, key string) (context.Context, float32, bool) {
 	v := ctx.Value(key)
 	if v == nil { // either no tuning or masked to avoid Do() recursion
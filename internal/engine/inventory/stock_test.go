@@ -5,6 +5,7 @@ import (
 	fmt "fmt"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,7 @@ func TestStockErrors(t *testing.T) {
 		{"ignore", _CS{Name: "b", Check: true, SpendRate: 100, RegisterAdd: "ignore(?)"}, func(t testing.TB, s string) { assert.Equal(t, ErrStockLow.Error(), s) }},
 		{"ignore+unknown", _CS{Name: "d", RegisterAdd: "ignore(?) foobar"}, func(t testing.TB, s string) { assert.Contains(t, s, "foobar not resolved") }},
 		{"fail", _CS{Name: "e", RegisterAdd: "ignore(?) fail"}, func(t testing.TB, s string) { assert.Contains(t, s, "expected error") }},
+		{"shelf-life", _CS{Name: "f", ShelfLife: "week"}, func(t testing.TB, s string) { assert.Contains(t, s, "stock=f shelf_life") }},
 	}
 	for _, c := range cases {
 		c := c
@@ -104,3 +106,232 @@ func TestSpendValue(t *testing.T) {
 	}
 	assert.NoError(t, quick.Check(f, &quick.Config{MaxCount: 10000}))
 }
+
+func TestStockHasCond(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	s, err := NewStock(engine_config.Stock{Name: "milk", SpendRate: 2, Min: 5}, e)
+	require.NoError(t, err)
+	e.RegisterNewFunc("milk", func(context.Context) error { return nil })
+	e.RegisterNewFunc("cream", func(context.Context) error { return errors.New("cream") })
+	d, err := e.ParseText("drink", "if(stock.milk.has(10)) milk else cream end")
+	require.NoError(t, err)
+
+	s.Set(25)
+	assert.NoError(t, e.Exec(ctx, d))
+	s.Set(24)
+	assert.EqualError(t, e.Exec(ctx, d), "cream")
+	s.Disable()
+	assert.NoError(t, e.Exec(ctx, d), "disabled stock is not tracked")
+}
+
+func TestStockAddArgs(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	var hw []engine.Arg
+	e.Register("run(?)", engine.FuncArg{
+		Name:   "run",
+		Params: []engine.Param{{Name: "units"}, {Name: "mode", Optional: true}},
+		FArgs:  func(_ context.Context, args []engine.Arg) error { hw = args; return nil },
+	})
+	s, err := NewStock(engine_config.Stock{Name: "sugar", HwRate: 2, RegisterAdd: "run(?)"}, e)
+	require.NoError(t, err)
+	s.Set(100)
+
+	e.TestDo(t, ctx, "add.sugar(5,mode=1)")
+	assert.Equal(t, []engine.Arg{10, 1}, hw)
+	assert.Equal(t, float32(95), s.Value())
+	assert.Error(t, e.Resolve("add.sugar(mode=1)").Validate())
+}
+
+func TestStockHwTable(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	var hw engine.Arg
+	e.Register("valve(?)", engine.FuncArg{Name: "valve", F: func(_ context.Context, a engine.Arg) error { hw = a; return nil }})
+	s, err := NewStock(engine_config.Stock{
+		Name:        "water",
+		HwRate:      2,
+		HwTable:     [][]float32{{100, 65}, {10, 7}, {300, 198}},
+		RegisterAdd: "valve(?)",
+	}, e)
+	require.NoError(t, err)
+	for _, c := range []struct{ arg, expect int }{{0, 0}, {1, 1}, {5, 4}, {10, 7}, {55, 36}, {100, 65}, {300, 198}, {400, 265}, {-10, -7}} {
+		assert.Equal(t, float32(c.expect), s.TranslateHw(engine.Arg(c.arg)), "arg=%d", c.arg)
+	}
+	e.TestDo(t, ctx, "add.water(200)")
+	assert.Equal(t, engine.Arg(132), hw)
+
+	for _, bad := range [][][]float32{{{10}}, {{10, 0}}, {{10, 7}, {10, 8}}, {{10, 7}, {20, 6}}} {
+		_, err := NewStock(engine_config.Stock{Name: "bad", HwTable: bad}, e)
+		assert.Error(t, err, "hw_table=%v", bad)
+	}
+
+	// calibration procedure
+	require.Error(t, e.Exec(ctx, e.Resolve("stock.water.measured(11)")), "measured without calibrate")
+	require.Error(t, e.Exec(ctx, e.Resolve("stock.water.calibration")), "no points")
+	e.TestDo(t, ctx, "stock.water.calibrate(10)")
+	assert.Equal(t, engine.Arg(10), hw)
+	e.TestDo(t, ctx, "stock.water.measured(12)")
+	for _, measured := range []string{"140", "150"} { // repeated dose replaces point
+		e.TestDo(t, ctx, "stock.water.calibrate(100)")
+		e.TestDo(t, ctx, "stock.water.measured("+measured+")")
+	}
+	line, err := s.ApplyCalibration()
+	require.NoError(t, err)
+	assert.Equal(t, `stock "water" { hw_table = [[12, 10], [150, 100]] }`, line)
+	assert.Equal(t, float32(55), s.TranslateHw(81))
+}
+
+func TestStockCalibrationPersist(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	root := t.TempDir()
+	config := &engine_config.Inventory{Stocks: []_CS{
+		{Name: "water", HwRate: 2, RegisterAdd: "valve(?)"},
+		{Name: "milk", HwTable: [][]float32{{10, 7}, {100, 65}}, RegisterAdd: "valve(?)"},
+	}}
+	load := func() (context.Context, *engine.Engine, *Inventory) {
+		e := engine.NewEngine(log)
+		e.Register("valve(?)", engine.FuncArg{Name: "valve", F: func(context.Context, engine.Arg) error { return nil }})
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, log2.ContextKey, log)
+		ctx = context.WithValue(ctx, engine.ContextKey, e)
+		inv := &Inventory{}
+		require.NoError(t, inv.Init(ctx, config, e))
+		require.NoError(t, inv.Persist.Init("inventory", inv, root, true, log))
+		require.NoError(t, inv.Persist.Load())
+		return ctx, e, inv
+	}
+
+	ctx, e, inv := load()
+	assert.Equal(t, float32(162), inv.MustGet(t, "water").TranslateHw(81))
+	for _, point := range [][2]string{{"10", "12"}, {"100", "150"}} {
+		e.TestDo(t, ctx, "stock.water.calibrate("+point[0]+")")
+		e.TestDo(t, ctx, "stock.water.measured("+point[1]+")")
+	}
+	e.TestDo(t, ctx, "stock.water.calibration")
+	assert.Equal(t, float32(55), inv.MustGet(t, "water").TranslateHw(81))
+
+	// calibration survives reload, config hw_table of other stock is not persisted
+	_, _, inv = load()
+	assert.Equal(t, float32(55), inv.MustGet(t, "water").TranslateHw(81))
+	assert.Nil(t, inv.MustGet(t, "milk").calibratedTable())
+	assert.Equal(t, float32(36), inv.MustGet(t, "milk").TranslateHw(55))
+}
+
+func TestStockSimulation(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	e.Register("pour(?)", engine.FuncArg{Name: "pour", F: func(context.Context, engine.Arg) error {
+		return errors.New("hardware must not run in simulation")
+	}})
+	s, err := NewStock(engine_config.Stock{Name: "water", Check: true, HwRate: 0.5, SpendRate: 2, RegisterAdd: "pour(?)"}, e)
+	require.NoError(t, err)
+	s.Disable()
+	m, err := engine.ParseSimModel("pour", "1s", "100ms", false)
+	require.NoError(t, err)
+
+	simCtx, result := engine.WithSimulation(ctx, engine.NewSimulation(m))
+	e.TestDo(t, simCtx, "add.water(100)")
+	e.TestDo(t, simCtx, "stock.water.spend1")
+	assert.Equal(t, 6*time.Second, result.Duration())
+	assert.Equal(t, map[string]engine.SimSpend{"water": {Spend: 202, Hw: 51}}, result.Spend())
+}
+
+func TestStockBelowMin(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, e)
+	s, err := NewStock(engine_config.Stock{Name: "cup", Code: 4, Min: 10}, e)
+	require.NoError(t, err)
+	s.Set(11)
+
+	fired := make(chan engine.Arg, 1)
+	e.Register("test.fired(?)", engine.FuncArg{Name: "test.fired", F: func(ctx context.Context, a engine.Arg) error {
+		fired <- a
+		return nil
+	}})
+	d, err := e.ParseText("hook", "test.fired(${code}*100+${value})")
+	require.NoError(t, err)
+	require.NoError(t, e.Hook("stock_below_min:cup", d))
+	noEvent := func(msg string) {
+		select {
+		case a := <-fired:
+			t.Fatalf("%s arg=%d", msg, a)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	require.NoError(t, e.Exec(ctx, e.Resolve("stock.cup.spend1")))
+	noEvent("fired at min")
+	require.NoError(t, e.Exec(ctx, e.Resolve("stock.cup.spend1")))
+	assert.Equal(t, engine.Arg(409), <-fired)
+	require.NoError(t, e.Exec(ctx, e.Resolve("stock.cup.spend1")))
+	noEvent("only crossing min emits")
+}
+
+func TestStockCapacity(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	e := engine.NewEngine(log)
+	ctx := context.WithValue(context.Background(), log2.ContextKey, log)
+	config := &engine_config.Inventory{Stocks: []_CS{{Name: "cup", Capacity: 80}, {Name: "water"}}}
+	inv := &Inventory{}
+	require.NoError(t, inv.Init(ctx, config, e))
+	cup, water := inv.MustGet(t, "cup"), inv.MustGet(t, "water")
+
+	cup.Set(20)
+	fill, ok := cup.Fill()
+	assert.True(t, ok)
+	assert.Equal(t, float32(25), fill)
+	amount, err := cup.RefillFull(SourceService)
+	require.NoError(t, err)
+	assert.Equal(t, float32(60), amount)
+	assert.Equal(t, float32(80), cup.Value())
+	assert.Error(t, cup.Refill(SourceService, -1))
+
+	_, ok = water.Fill()
+	assert.False(t, ok)
+	_, err = water.RefillFull(SourceService)
+	assert.True(t, errors.IsNotValid(err))
+	require.NoError(t, water.Refill(SourceService, 5))
+	assert.Equal(t, float32(5), water.Value())
+
+	// capacity set remotely survives restart unless config has it
+	water.SetCapacity(10)
+	cup.SetCapacity(100)
+	b, err := inv.MarshalBinary()
+	require.NoError(t, err)
+	inv2 := &Inventory{}
+	require.NoError(t, inv2.Init(ctx, config, engine.NewEngine(log)))
+	require.NoError(t, inv2.UnmarshalBinary(b))
+	assert.Equal(t, float32(80), inv2.MustGet(t, "cup").Capacity())
+	assert.Equal(t, float32(10), inv2.MustGet(t, "water").Capacity())
+}
@@ -3,6 +3,7 @@ package inventory
 import (
 	fmt "fmt"
 	"sort"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/temoto/vender/helpers"
@@ -33,7 +34,14 @@ func (self *Inventory) SetTele(src *tele_api.Inventory) (*tele_api.Inventory, er
 			break
 		}
 		if stock, ok := self.locked_get(new.Code, new.Name); ok {
-			stock.Set(new.Valuef)
+			if new.Capacity != 0 {
+				stock.SetCapacity(new.Capacity)
+			}
+			var expire time.Time
+			if new.Expire != 0 {
+				expire = time.Unix(0, new.Expire)
+			}
+			stock.SetLot(SourceTele, new.Valuef, expire)
 		}
 	}
 
@@ -48,6 +56,7 @@ func (self *Inventory) Tele() *tele_api.Inventory {
 
 func (self *Inventory) locked_tele() *tele_api.Inventory {
 	pb := &tele_api.Inventory{Stocks: make([]*tele_api.Inventory_StockItem, 0, 16)}
+	now := time.Now()
 
 	for _, s := range self.byName {
 		if s.Enabled() {
@@ -60,6 +69,16 @@ func (self *Inventory) locked_tele() *tele_api.Inventory {
 			if self.config.TeleAddName {
 				si.Name = s.Name
 			}
+			var at time.Time
+			if si.Rate, at = s.Forecast(now); !at.IsZero() {
+				si.ForecastMin = at.UnixNano()
+			}
+			si.Capacity = s.Capacity()
+			si.Fill, _ = s.Fill()
+			if expire := s.Expire(); !expire.IsZero() {
+				si.Expire = expire.UnixNano()
+				si.ExpireSoon = s.ExpireSoon(now)
+			}
 			pb.Stocks = append(pb.Stocks, si)
 		}
 	}
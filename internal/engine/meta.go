@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Meta describes registered action for service tools and config editor.
+type Meta struct {
+	Description string
+	Device      string  // owning device, empty for engine and config actions
+	Params      []Param // default is Params of action FuncArg
+	Safe        bool    // may run in customer scenario, false for service and unknown actions
+}
+
+// ActionInfo is exported description of one registered action.
+type ActionInfo struct {
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"` // action or condition
+	Description string  `json:"description,omitempty"`
+	Device      string  `json:"device,omitempty"`
+	Params      []Param `json:"params,omitempty"`
+	Safe        bool    `json:"safe"`
+}
+
+// Describe returns info of action, `foo(5)` is looked up as `foo(?)`.
+func (self *Engine) Describe(action string) (ActionInfo, bool) {
+	self.lk.RLock()
+	defer self.lk.RUnlock()
+	if _, ok := self.actions[action]; !ok {
+		tok := parseArg(action)
+		if _, ok = self.actions[tok.norm]; !tok.ok || !ok {
+			return ActionInfo{}, false
+		}
+		action = tok.norm
+	}
+	return self.locked_describe(action), true
+}
+
+// Catalog returns info of all registered actions sorted by name.
+func (self *Engine) Catalog() []ActionInfo {
+	self.lk.RLock()
+	defer self.lk.RUnlock()
+	result := make([]ActionInfo, 0, len(self.actions))
+	for name := range self.actions {
+		result = append(result, self.locked_describe(name))
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result
+}
+
+// ExportJSON writes Catalog as JSON array.
+func (self *Engine) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(self.Catalog())
+}
+
+func (self *Engine) locked_describe(name string) ActionInfo {
+	d := self.actions[name]
+	meta := self.meta[name]
+	info := ActionInfo{
+		Name:        name,
+		Kind:        "action",
+		Description: meta.Description,
+		Device:      meta.Device,
+		Params:      meta.Params,
+		Safe:        meta.Safe,
+	}
+	if _, ok := d.(Tester); ok {
+		info.Kind = "condition"
+	}
+	if info.Params == nil && parseArg(name).arg == "?" {
+		info.Params = findParams(d)
+	}
+	return info
+}
+
+// findParams returns Params of first FuncArg in d.
+func findParams(d Doer) []Param {
+	switch x := d.(type) {
+	case FuncArg:
+		if x.Params == nil {
+			return defaultParams
+		}
+		return x.Params
+	case CondArg:
+		return defaultParams
+	case Composite:
+		for _, child := range x.Children() {
+			if child == nil {
+				continue
+			}
+			if params := findParams(child); params != nil {
+				return params
+			}
+		}
+	}
+	return nil
+}
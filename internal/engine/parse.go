@@ -0,0 +1,394 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Scenario language:
+//   action action(1,2) action(${var}*2)
+//   if(cond) ... else ... end   if(!cond) ... end
+//   par(a b c)   retry(3, a b)   timeout(5s, a b)
+// Words are separated by whitespace, group starts `par(`, `retry(N,`, `timeout(D,` and `)` are words too.
+
+// Pos is location in config file, or in scenario text if File is unknown. Line and Col start at 1.
+type Pos struct {
+	File string
+	Line int
+	Col  int
+}
+
+func (self Pos) String() string {
+	if self.File == "" {
+		return fmt.Sprintf("%d:%d", self.Line, self.Col)
+	}
+	return fmt.Sprintf("%s:%d:%d", self.File, self.Line, self.Col)
+}
+
+// Source is scenario text with its origin for error messages.
+type Source struct {
+	Tag  string // name of resulting Seq
+	Text string
+	Pos  Pos // of first Text byte, zero if unknown
+}
+
+type NodeKind uint8
+
+const (
+	NodeAction NodeKind = iota
+	NodeIf
+	NodePar
+	NodeRetry
+	NodeTimeout
+)
+
+// Node is scenario syntax tree element.
+type Node struct {
+	Kind  NodeKind
+	Pos   Pos
+	Word  string // source word, `if(!cond)`, `retry(3,`
+	Name  string // action, condition without `!`, retry count, timeout duration
+	Not   bool
+	Body  []*Node // If then, Par/Retry/Timeout contents
+	Else  []*Node
+	Retry uint
+	Time  time.Duration
+}
+
+// ParseError points at word in scenario source.
+type ParseError struct {
+	Pos  Pos
+	Tag  string
+	Word string
+	Err  error
+}
+
+func (self *ParseError) Error() string {
+	return fmt.Sprintf("%s scenario=%s word=%s: %v", self.Pos.String(), self.Tag, self.Word, self.Err)
+}
+func (self *ParseError) Cause() error { return self.Err }
+
+type scanToken struct {
+	word string
+	pos  Pos
+}
+
+var reIf = regexp.MustCompile(`^if\((!?)(.+)\)$`)
+
+const wordPar = "par("
+
+// Group start with parameter, `retry(3,` and `timeout(5s,`.
+var reWrap = regexp.MustCompile(`^(retry|timeout)\(([^\s(),]+),`)
+
+// scanTokens splits scenario by whitespace, keeps whitespace inside parens, `foo(${a} * 2)`.
+func scanTokens(src Source) []scanToken {
+	origin := src.Pos
+	if origin.Line == 0 {
+		origin = Pos{File: origin.File, Line: 1, Col: 1}
+	}
+	text := src.Text
+	toks := make([]scanToken, 0, 16)
+	line, lineStart := 0, 0
+	posAt := func(i int) Pos {
+		for j := lineStart; j < i; j++ {
+			if text[j] == '\n' {
+				line++
+				lineStart = j + 1
+			}
+		}
+		if line == 0 {
+			return Pos{File: origin.File, Line: origin.Line, Col: origin.Col + i}
+		}
+		return Pos{File: origin.File, Line: origin.Line + line, Col: i - lineStart + 1}
+	}
+	add := func(start, end int) {
+		toks = append(toks, scanToken{word: text[start:end], pos: posAt(start)})
+	}
+
+	for i := 0; i < len(text); {
+		switch {
+		case isSpace(text[i]):
+			i++
+			continue
+		case text[i] == ')':
+			add(i, i+1)
+			i++
+			continue
+		case strings.HasPrefix(text[i:], wordPar):
+			add(i, i+len(wordPar))
+			i += len(wordPar)
+			continue
+		}
+		if m := reWrap.FindString(text[i:]); m != "" {
+			add(i, i+len(m))
+			i += len(m)
+			continue
+		}
+
+		start, depth := i, 0
+	word:
+		for ; i < len(text) && (depth > 0 || !isSpace(text[i])); i++ {
+			switch text[i] {
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break word
+				}
+				depth--
+			}
+		}
+		add(start, i)
+	}
+	return toks
+}
+
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// ParseAST returns syntax tree of scenario, does not resolve actions.
+func ParseAST(src Source) ([]*Node, error) {
+	p := astParser{src: src, toks: scanTokens(src)}
+	nodes, end, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	if end != nil {
+		return nil, p.errorf(*end, "unexpected")
+	}
+	return nodes, nil
+}
+
+type astParser struct {
+	src  Source
+	toks []scanToken
+	pos  int
+}
+
+func (self *astParser) errorf(tok scanToken, format string, args ...interface{}) error {
+	return &ParseError{Pos: tok.pos, Tag: self.src.Tag, Word: tok.word, Err: errors.Errorf(format, args...)}
+}
+
+// parseList consumes words until end of text or block terminator `else`/`end`/`)`, which is returned.
+func (self *astParser) parseList() ([]*Node, *scanToken, error) {
+	nodes := make([]*Node, 0, len(self.toks)-self.pos)
+	for self.pos < len(self.toks) {
+		tok := self.toks[self.pos]
+		self.pos++
+		switch tok.word {
+		case "else", "end", ")":
+			return nodes, &tok, nil
+		}
+
+		node := &Node{Kind: NodeAction, Pos: tok.pos, Word: tok.word, Name: tok.word}
+		var err error
+		if tok.word == wordPar {
+			node.Kind = NodePar
+			node.Name = ""
+			node.Body, err = self.parseGroup(tok)
+		} else if m := reWrap.FindStringSubmatch(tok.word); m != nil {
+			err = self.parseWrap(tok, node, m[1], m[2])
+		} else if m := reIf.FindStringSubmatch(tok.word); m != nil {
+			node.Kind = NodeIf
+			node.Name = m[2]
+			node.Not = m[1] == "!"
+			err = self.parseIf(tok, node)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil, nil
+}
+
+// parseGroup reads contents until `)`.
+func (self *astParser) parseGroup(start scanToken) ([]*Node, error) {
+	body, end, err := self.parseList()
+	if err != nil {
+		return nil, err
+	}
+	if end == nil || end.word != ")" {
+		return nil, self.errorf(start, "missing )")
+	}
+	return body, nil
+}
+
+func (self *astParser) parseWrap(tok scanToken, node *Node, kind, param string) error {
+	node.Name = param
+	switch kind {
+	case "retry":
+		node.Kind = NodeRetry
+		n, err := strconv.ParseUint(param, 10, 16)
+		if err != nil || n == 0 {
+			return self.errorf(tok, "retry count=%s not valid", param)
+		}
+		node.Retry = uint(n)
+	case "timeout":
+		node.Kind = NodeTimeout
+		duration, err := time.ParseDuration(param)
+		if err != nil || duration <= 0 {
+			return self.errorf(tok, "timeout duration=%s not valid", param)
+		}
+		node.Time = duration
+	}
+	var err error
+	if node.Body, err = self.parseGroup(tok); err != nil {
+		return err
+	}
+	if len(node.Body) == 0 {
+		return self.errorf(tok, "%s() is empty", kind)
+	}
+	return nil
+}
+
+func (self *astParser) parseIf(tok scanToken, node *Node) error {
+	var end *scanToken
+	var err error
+	if node.Body, end, err = self.parseList(); err != nil {
+		return err
+	}
+	if end != nil && end.word == "else" {
+		if node.Else, end, err = self.parseList(); err != nil {
+			return err
+		}
+	}
+	if end == nil || end.word != "end" {
+		return self.errorf(tok, "missing end")
+	}
+	return nil
+}
+
+// ParseText compiles scenario, see ParseSource.
+func (self *Engine) ParseText(tag, text string) (Doer, error) {
+	return self.ParseSource(Source{Tag: tag, Text: text})
+}
+
+// Compiled scenarios are cached until next Register, cache is reset when full.
+const parseCacheSize = 1024
+
+type parseCacheKey struct{ tag, text string }
+
+// ParseSource compiles scenario to Seq named src.Tag.
+// Actions are resolved now if registered, otherwise on first use.
+func (self *Engine) ParseSource(src Source) (Doer, error) {
+	key := parseCacheKey{src.Tag, src.Text}
+	self.parseCache.Lock()
+	d, ok := self.parseCache.m[key]
+	self.parseCache.Unlock()
+	if ok {
+		return d, nil
+	}
+
+	nodes, err := ParseAST(src)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := self.compile(src, src.Tag, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	self.parseCache.Lock()
+	if self.parseCache.m == nil || len(self.parseCache.m) >= parseCacheSize {
+		self.parseCache.m = make(map[parseCacheKey]Doer)
+	}
+	self.parseCache.m[key] = seq
+	self.parseCache.Unlock()
+	return seq, nil
+}
+
+func (self *Engine) parseCacheReset() {
+	self.parseCache.Lock()
+	self.parseCache.m = nil
+	self.parseCache.Unlock()
+}
+
+func (self *Engine) compile(src Source, tag string, nodes []*Node) (*Seq, error) {
+	seq := NewSeq(tag)
+	for _, node := range nodes {
+		d, err := self.compileNode(src, tag, node)
+		if err != nil {
+			if _, ok := err.(*ParseError); !ok {
+				err = &ParseError{Pos: node.Pos, Tag: src.Tag, Word: node.Word, Err: err}
+			}
+			return nil, err
+		}
+		seq.Append(d)
+	}
+	return seq, nil
+}
+
+// resolveNode is ResolveOrLazy remembering node position for late resolve errors.
+func (self *Engine) resolveNode(node *Node) (Doer, error) {
+	d, err := self.ResolveOrLazy(node.Name)
+	if l, ok := d.(*Lazy); ok {
+		l.Pos = node.Pos
+	}
+	return d, err
+}
+
+func (self *Engine) compileNode(src Source, tag string, node *Node) (Doer, error) {
+	switch node.Kind {
+	case NodeAction:
+		return self.resolveNode(node)
+
+	case NodeIf:
+		c, err := self.resolveNode(node)
+		if err != nil {
+			return nil, err
+		}
+		d := &If{Cond: c, Not: node.Not}
+		if d.Then, err = self.compile(src, d.String()+"/then", node.Body); err != nil {
+			return nil, err
+		}
+		if node.Else != nil {
+			if d.Else, err = self.compile(src, d.String()+"/else", node.Else); err != nil {
+				return nil, err
+			}
+		}
+		return d, nil
+
+	case NodePar:
+		inner, err := self.compile(src, tag, node.Body)
+		if err != nil {
+			return nil, err
+		}
+		return NewPar(inner.items...), nil
+
+	case NodeRetry, NodeTimeout:
+		inner, err := self.compile(src, tag, node.Body)
+		if err != nil {
+			return nil, err
+		}
+		var d Doer
+		if len(inner.items) == 1 {
+			d = inner.items[0]
+		} else {
+			names := make([]string, len(inner.items))
+			for i, x := range inner.items {
+				names[i] = x.String()
+			}
+			seq := NewSeq(strings.Join(names, " "))
+			for _, x := range inner.items {
+				seq.Append(x)
+			}
+			d = seq
+		}
+		if node.Kind == NodeRetry {
+			return &Retry{N: node.Retry, D: d}, nil
+		}
+		return &Timeout{Duration: node.Time, D: d}, nil
+	}
+	panic(fmt.Sprintf("code error compile node kind=%d", node.Kind))
+}
@@ -1,14 +1,61 @@
 package engine
 
 import (
+	"math"
 	"regexp"
+	"sort"
 	"sync/atomic"
 	"time"
 )
 
+// Latency percentiles are computed over last profileSamples durations.
+const profileSamples = 256
+
 type ProfileFunc func(Doer, time.Duration)
 
+// Latency summarizes durations of one action since last TakeLatency.
+type Latency struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+type latencyHist struct {
+	count   int
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+func (self *latencyHist) add(d time.Duration) {
+	self.count++
+	if d > self.max {
+		self.max = d
+	}
+	if len(self.samples) < profileSamples {
+		self.samples = append(self.samples, d)
+	} else {
+		self.samples[self.next] = d
+		self.next = (self.next + 1) % profileSamples
+	}
+}
+
+func (self *latencyHist) summary() Latency {
+	sorted := append([]time.Duration(nil), self.samples...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+	p := func(q float64) time.Duration {
+		i := int(math.Ceil(q*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+	return Latency{Count: self.count, P50: p(0.5), P95: p(0.95), Max: self.max}
+}
+
 // re=nil or fun=nil to disable profiling.
+// Actions matching re are measured for TakeLatency, fun is called for durations >= min.
 func (self *Engine) SetProfile(re *regexp.Regexp, min time.Duration, fun ProfileFunc) {
 	fast := uint32(0)
 	if re != nil || fun != nil {
@@ -22,14 +69,41 @@ func (self *Engine) SetProfile(re *regexp.Regexp, min time.Duration, fun Profile
 	self.profile.min = min
 }
 
-func (self *Engine) matchProfile(s string) (ProfileFunc, time.Duration) {
+// TakeLatency returns latency of profiled actions by name and starts new period.
+func (self *Engine) TakeLatency() map[string]Latency {
+	self.profile.Lock()
+	hist := self.profile.hist
+	self.profile.hist = nil
+	self.profile.Unlock()
+	result := make(map[string]Latency, len(hist))
+	for name, h := range hist {
+		result[name] = h.summary()
+	}
+	return result
+}
+
+func (self *Engine) matchProfile(s string) (ProfileFunc, time.Duration, bool) {
 	if atomic.LoadUint32(&self.profile.fastpath) != 1 {
-		return nil, 0
+		return nil, 0, false
 	}
 	self.profile.Lock()
 	defer self.profile.Unlock()
 	if self.profile.re != nil && self.profile.re.MatchString(s) {
-		return self.profile.fun, self.profile.min
+		return self.profile.fun, self.profile.min, true
+	}
+	return nil, 0, false
+}
+
+func (self *Engine) profileRecord(name string, d time.Duration) {
+	self.profile.Lock()
+	defer self.profile.Unlock()
+	if self.profile.hist == nil {
+		self.profile.hist = make(map[string]*latencyHist)
+	}
+	h := self.profile.hist[name]
+	if h == nil {
+		h = &latencyHist{}
+		self.profile.hist[name] = h
 	}
-	return nil, 0
+	h.add(d)
 }
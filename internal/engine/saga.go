@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/helpers"
+)
+
+const sagaContextKey = "run/engine-saga"
+
+// Saga collects compensations of completed steps during one execution.
+// On failure Rollback runs them in reverse order.
+type Saga struct {
+	mu   sync.Mutex
+	undo []Doer
+}
+
+// WithSaga returns ctx where Compensate steps record their Undo.
+func WithSaga(ctx context.Context) (context.Context, *Saga) {
+	saga := &Saga{}
+	return context.WithValue(ctx, sagaContextKey, saga), saga
+}
+
+func getSaga(ctx context.Context) *Saga {
+	saga, _ := ctx.Value(sagaContextKey).(*Saga)
+	return saga
+}
+
+func (self *Saga) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return len(self.undo)
+}
+
+func (self *Saga) push(d Doer) {
+	self.mu.Lock()
+	self.undo = append(self.undo, d)
+	self.mu.Unlock()
+}
+
+// merge moves compensations of nested execution into self.
+func (self *Saga) merge(child *Saga) {
+	child.mu.Lock()
+	undo := child.undo
+	child.undo = nil
+	child.mu.Unlock()
+	self.mu.Lock()
+	self.undo = append(self.undo, undo...)
+	self.mu.Unlock()
+}
+
+// Rollback executes collected compensations, last completed step first.
+// Continues after errors, so every completed step gets a chance to clean up.
+func (self *Saga) Rollback(ctx context.Context) error {
+	self.mu.Lock()
+	undo := self.undo
+	self.undo = nil
+	self.mu.Unlock()
+
+	e := GetGlobal(ctx)
+	errs := make([]error, 0)
+	for i := len(undo) - 1; i >= 0; i-- {
+		d := undo[i]
+		e.Log.Debugf("saga rollback %s", d.String())
+		if err := e.ValidateExec(ctx, d); err != nil {
+			errs = append(errs, errors.Annotatef(err, "rollback %s", d.String()))
+		}
+	}
+	return helpers.FoldErrors(errs)
+}
+
+// Compensate is step D with compensation Undo, alias config `undo = "..."`.
+// After D completes, Undo replaces compensations of nested steps in Saga.
+// After D fails, compensations of its completed nested steps are kept.
+type Compensate struct {
+	D    Doer
+	Undo Doer
+}
+
+func (self *Compensate) Validate() error { return self.D.Validate() }
+func (self *Compensate) Do(ctx context.Context) error {
+	e := GetGlobal(ctx)
+	parent := getSaga(ctx)
+	if parent == nil {
+		return e.Exec(ctx, self.D)
+	}
+	childCtx, child := WithSaga(ctx)
+	if err := e.Exec(childCtx, self.D); err != nil {
+		parent.merge(child)
+		return err
+	}
+	parent.push(self.Undo)
+	return nil
+}
+func (self *Compensate) String() string { return self.D.String() }
+
+func (self *Compensate) Apply(args Args) (Doer, bool, error) {
+	new, applied, err := ArgsApply(self.D, args)
+	if err != nil {
+		return nil, false, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	return &Compensate{D: new, Undo: self.Undo}, applied, nil
+}
+
+func (self *Compensate) Force() (Doer, bool, error) {
+	new, forced, err := Force(self.D)
+	if err != nil {
+		return nil, forced, errors.Annotatef(err, FmtErrContext, self.String())
+	}
+	if !forced {
+		return self, false, nil
+	}
+	return &Compensate{D: new, Undo: self.Undo}, true, nil
+}
+
+func (self *Compensate) Children() []Doer { return []Doer{self.D, self.Undo} }
+
+// compile-time interface checks
+var _ ArgApplier = &Compensate{}
+var _ Composite = &Compensate{}
+var _ Forcer = &Compensate{}
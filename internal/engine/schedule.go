@@ -1,8 +1,10 @@
 package engine
 
 import (
+	"container/heap"
 	"context"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/temoto/alive/v2"
@@ -10,16 +12,109 @@ import (
 	tele_api "github.com/temoto/vender/tele"
 )
 
+const queueDeadlineKey = "run/engine-queue-deadline"
+
+// WithQueueDeadline limits only time in scheduler queue: task is ordered by deadline
+// and dropped if it passes before start. Started task runs with ctx without this deadline.
+func WithQueueDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, queueDeadlineKey, deadline)
+}
+
+// Task order: priority class, then earliest deadline, then FIFO.
+// Now jumps ahead of pending work, idle tasks run when nothing else is queued.
 type task struct {
-	fun  types.TaskFunc
-	done chan error
-	pri  tele_api.Priority
+	ctx      context.Context
+	fun      types.TaskFunc
+	done     chan error
+	pri      tele_api.Priority
+	deadline time.Time // zero = no deadline, ctx or queue deadline
+	seq      uint64
+	queued   time.Time
+	index    int // in heap, -1 when removed
+}
+
+func (t *task) class() int {
+	switch {
+	case t.pri&tele_api.Priority_Now != 0:
+		return 0
+	case wantIdle(t.pri):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// expired returns error if task must not start.
+func (t *task) expired(now time.Time) error {
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+	if !t.deadline.IsZero() && now.After(t.deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
 }
 
+func (t *task) exclusive() bool { return t.pri&tele_api.Priority_IdleEngine != 0 }
+
+type taskQueue []*task
+
+func (q taskQueue) Len() int { return len(q) }
+func (q taskQueue) Less(i, j int) bool {
+	a, b := q[i], q[j]
+	if ca, cb := a.class(), b.class(); ca != cb {
+		return ca < cb
+	}
+	if !a.deadline.Equal(b.deadline) {
+		if a.deadline.IsZero() || b.deadline.IsZero() {
+			return b.deadline.IsZero()
+		}
+		return a.deadline.Before(b.deadline)
+	}
+	return a.seq < b.seq
+}
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *taskQueue) Push(x interface{}) {
+	t := x.(*task)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*q = old[:n-1]
+	return t
+}
+
+// RunStats are scheduler queue metrics.
+type RunStats struct {
+	Queued    int           // waiting now
+	MaxQueued int           // max waiting since NewRunner
+	Done      uint64        // executed tasks
+	Expired   uint64        // deadline passed or canceled before execution
+	MaxWait   time.Duration // longest time in queue of executed task
+}
+
+// Run starts tasks in priority order, see task.
+// Default and Now tasks run concurrently, IdleEngine waits for them and runs exclusively.
+// Deadline of task is deadline of its ctx.
 type Run struct {
 	alive *alive.Alive
-	q     chan task
+	wake  chan struct{}
 	idle  sync.RWMutex
+
+	mu      sync.Mutex
+	q       taskQueue
+	seq     uint64
+	stats   RunStats
+	stopped bool
 }
 
 var _ types.Scheduler = &Run{} // compile-time interface test
@@ -27,77 +122,202 @@ var _ types.Scheduler = &Run{} // compile-time interface test
 func NewRunner() *Run {
 	return &Run{
 		alive: alive.NewAlive(),
-		q:     make(chan task),
+		wake:  make(chan struct{}, 1),
 	}
 }
 
 func (r *Run) Loop(ctx context.Context, parent *alive.Alive) {
 	defer r.alive.WaitTasks()
+	defer r.drain()
 	myStop := r.alive.StopChan()
 	parentStop := parent.StopChan()
+	defer r.alive.Stop()
 	for parent.IsRunning() && r.alive.IsRunning() {
+		if exclusive, ok := r.peek(); ok {
+			r.dispatch(exclusive)
+			continue
+		}
 		select {
-		case t := <-r.q:
-			r.alive.Add(1)
-			go r.doTask(ctx, t)
+		case <-r.wake:
 
 		case <-parentStop:
 			r.alive.Stop()
-			// return errors.Errorf("Run.Loop interrupted, ignore like EPIPE")
 			return
 
 		case <-myStop:
-			// return errors.Errorf("Run.Loop interrupted, ignore like EPIPE")
 			return
 		}
 	}
 }
 
+func (r *Run) Stop() { r.alive.Stop() }
+
+// Schedule enqueues fun, result is sent to returned chan once.
+// ctx is passed to fun, its deadline or WithQueueDeadline orders queue.
 func (r *Run) Schedule(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc) chan error {
-	if !r.alive.IsRunning() {
-		return nil
-	}
-	ch := make(chan error)
-	r.q <- task{
-		done: ch,
-		fun:  fun,
-		pri:  priority,
+	ch := make(chan error, 1)
+	if _, err := r.push(ctx, priority, fun, ch); err != nil {
+		ch <- err
 	}
 	return ch
 }
 
 func (r *Run) ScheduleSync(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc) error {
-	if !r.alive.IsRunning() {
-		return errors.Trace(types.ErrInterrupted)
+	ch := make(chan error, 1)
+	t, err := r.push(ctx, priority, fun, ch)
+	if err != nil {
+		return err
+	}
+	var expire <-chan time.Time
+	if !t.deadline.IsZero() {
+		tmr := time.NewTimer(time.Until(t.deadline))
+		defer tmr.Stop()
+		expire = tmr.C
+	}
+	select {
+	case err = <-ch:
+		return err
+	case <-expire:
+		if r.remove(t) {
+			return errors.Trace(context.DeadlineExceeded)
+		}
+		// already executing
+		return <-ch
+	case <-ctx.Done():
+		if r.remove(t) {
+			return errors.Trace(ctx.Err())
+		}
+		// already executing, fun sees ctx.Done
+		return <-ch
 	}
-	r.alive.Add(1)
-	return r.do(ctx, priority, fun)
 }
 
-func (r *Run) do(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc) error {
-	defer r.alive.Done()
+func (r *Run) Stats() RunStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats
+	s.Queued = len(r.q)
+	return s
+}
 
-	if wantIdle(priority) {
-		r.idle.Lock()
-		defer r.idle.Unlock()
-	} else {
-		r.idle.RLock()
-		defer r.idle.RUnlock()
+func (r *Run) push(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc, ch chan error) (*task, error) {
+	if !r.alive.IsRunning() {
+		return nil, errors.Trace(types.ErrInterrupted)
+	}
+	t := &task{ctx: ctx, fun: fun, done: ch, pri: priority, queued: time.Now()}
+	t.deadline, _ = ctx.Deadline()
+	if qd, ok := ctx.Value(queueDeadlineKey).(time.Time); ok && !qd.IsZero() && (t.deadline.IsZero() || qd.Before(t.deadline)) {
+		t.deadline = qd
+	}
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, errors.Trace(types.ErrInterrupted)
 	}
-	// May be stopped while waiting for lock
+	r.seq++
+	t.seq = r.seq
+	heap.Push(&r.q, t)
+	if len(r.q) > r.stats.MaxQueued {
+		r.stats.MaxQueued = len(r.q)
+	}
+	r.mu.Unlock()
 	select {
-	case <-r.alive.StopChan():
-		return errors.Trace(types.ErrInterrupted)
+	case r.wake <- struct{}{}:
 	default:
 	}
+	return t, nil
+}
+
+// peek reports whether queue is not empty and first task needs exclusive run.
+func (r *Run) peek() (exclusive bool, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.q) == 0 {
+		return false, false
+	}
+	return r.q[0].exclusive(), true
+}
+
+// next pops task to execute if it matches exclusive lock held by caller.
+// Expired tasks are completed with ctx error.
+func (r *Run) next(exclusive bool) *task {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for len(r.q) != 0 {
+		t := r.q[0]
+		if err := t.expired(now); err != nil {
+			heap.Pop(&r.q)
+			r.stats.Expired++
+			t.done <- errors.Trace(err)
+			continue
+		}
+		if t.exclusive() != exclusive {
+			return nil
+		}
+		heap.Pop(&r.q)
+		if !r.alive.Add(1) {
+			t.done <- errors.Trace(types.ErrInterrupted)
+			continue
+		}
+		if wait := time.Since(t.queued); wait > r.stats.MaxWait {
+			r.stats.MaxWait = wait
+		}
+		return t
+	}
+	return nil
+}
 
-	return fun(ctx)
+func (r *Run) remove(t *task) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&r.q, t.index)
+	r.stats.Expired++
+	return true
 }
 
-func (r *Run) doTask(ctx context.Context, t task) {
-	err := r.do(ctx, t.pri, t.fun)
+// drain completes pending tasks on stop.
+func (r *Run) drain() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stopped = true
+	for len(r.q) != 0 {
+		t := heap.Pop(&r.q).(*task)
+		t.done <- errors.Trace(types.ErrInterrupted)
+	}
+}
+
+// dispatch takes idle lock, then runs first task in background.
+// Task stays in queue while waiting for lock, so it can still expire or be removed.
+func (r *Run) dispatch(exclusive bool) {
+	lock, unlock := r.idle.RLock, r.idle.RUnlock
+	if exclusive {
+		lock, unlock = r.idle.Lock, r.idle.Unlock
+	}
+	lock()
+	t := r.next(exclusive)
+	if t == nil {
+		unlock()
+		return
+	}
+	go func() {
+		defer unlock()
+		r.doTask(t)
+	}()
+}
+
+func (r *Run) doTask(t *task) {
+	defer r.alive.Done()
+	err := t.fun(t.ctx)
+	r.mu.Lock()
+	r.stats.Done++
+	r.mu.Unlock()
 	t.done <- err
-	close(t.done)
 }
 
-func wantIdle(p tele_api.Priority) bool { return p&tele_api.Priority_IdleEngine != 0 }
+func wantIdle(p tele_api.Priority) bool {
+	return p&(tele_api.Priority_IdleEngine|tele_api.Priority_IdleUser) != 0
+}
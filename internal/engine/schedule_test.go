@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/alive/v2"
+	"github.com/temoto/vender/internal/types"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+func TestScheduleOrder(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner()
+	order := make([]string, 0)
+	add := func(ctx context.Context, pri tele_api.Priority, name string) chan error {
+		return r.Schedule(ctx, pri, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	ctx := context.Background()
+	now := time.Now()
+	late, cancelLate := context.WithDeadline(ctx, now.Add(time.Hour))
+	defer cancelLate()
+	soon, cancelSoon := context.WithDeadline(ctx, now.Add(time.Minute))
+	defer cancelSoon()
+	expired, cancelExpired := context.WithDeadline(ctx, now.Add(-time.Second))
+	defer cancelExpired()
+
+	// queued before Loop starts, so order is deterministic
+	chs := []chan error{
+		add(ctx, tele_api.Priority_IdleEngine, "idle"),
+		add(ctx, tele_api.Priority_Default, "default1"),
+		add(late, tele_api.Priority_Default, "default-late"),
+		add(ctx, tele_api.Priority_Default, "default2"),
+		add(soon, tele_api.Priority_Default, "default-soon"),
+		add(ctx, tele_api.Priority_Now, "now"),
+	}
+	chExpired := add(expired, tele_api.Priority_Now, "expired")
+	assert.Equal(t, 7, r.Stats().Queued)
+
+	// dispatch order without concurrency of Loop
+	for exclusive, ok := r.peek(); ok; exclusive, ok = r.peek() {
+		if task := r.next(exclusive); task != nil {
+			r.doTask(task)
+		}
+	}
+	for _, ch := range chs {
+		require.NoError(t, <-ch)
+	}
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(<-chExpired))
+	assert.Equal(t, []string{"now", "default-soon", "default-late", "default1", "default2", "idle"}, order)
+	stats := r.Stats()
+	assert.Equal(t, 0, stats.Queued)
+	assert.Equal(t, 7, stats.MaxQueued)
+	assert.Equal(t, uint64(6), stats.Done)
+	assert.Equal(t, uint64(1), stats.Expired)
+
+	parent := alive.NewAlive()
+	go r.Loop(ctx, parent)
+	parent.Stop()
+	r.alive.Wait()
+	assert.Equal(t, types.ErrInterrupted, errors.Cause(r.ScheduleSync(ctx, tele_api.Priority_Default, nil)))
+}
+
+func TestScheduleConcurrent(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner()
+	parent := alive.NewAlive()
+	defer parent.Stop()
+	ctx := context.Background()
+	go r.Loop(ctx, parent)
+
+	var started sync.WaitGroup
+	started.Add(2)
+	finished := int32(0)
+	overlap := func(context.Context) error {
+		started.Done()
+		both := make(chan struct{})
+		go func() { started.Wait(); close(both) }()
+		select {
+		case <-both:
+		case <-time.After(time.Second):
+			return errors.Errorf("default tasks did not overlap")
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&finished, 1)
+		return nil
+	}
+	ch1 := r.Schedule(ctx, tele_api.Priority_Default, overlap)
+	ch2 := r.Schedule(ctx, tele_api.Priority_Default, overlap)
+	started.Wait()
+	idleSaw := int32(-1)
+	chIdle := r.Schedule(ctx, tele_api.Priority_IdleEngine, func(context.Context) error {
+		idleSaw = atomic.LoadInt32(&finished)
+		return nil
+	})
+	require.NoError(t, <-ch1)
+	require.NoError(t, <-ch2)
+	require.NoError(t, <-chIdle)
+	assert.Equal(t, int32(2), idleSaw)
+}
+
+func TestScheduleSyncDeadline(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner()
+	parent := alive.NewAlive()
+	defer parent.Stop()
+	go r.Loop(context.Background(), parent)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	busy := r.Schedule(context.Background(), tele_api.Priority_IdleEngine, func(context.Context) error {
+		close(started)
+		<-block
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	called := false
+	err := r.ScheduleSync(ctx, tele_api.Priority_Now, func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	close(block)
+	require.NoError(t, <-busy)
+	assert.False(t, called)
+	assert.Equal(t, uint64(1), r.Stats().Expired)
+}
+
+func TestScheduleQueueDeadline(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner()
+	parent := alive.NewAlive()
+	defer parent.Stop()
+	go r.Loop(context.Background(), parent)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	busy := r.Schedule(context.Background(), tele_api.Priority_IdleEngine, func(context.Context) error {
+		close(started)
+		<-block
+		return nil
+	})
+	<-started
+
+	ctx := WithQueueDeadline(context.Background(), time.Now().Add(10*time.Millisecond))
+	called := false
+	err := r.ScheduleSync(ctx, tele_api.Priority_Now, func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	close(block)
+	require.NoError(t, <-busy)
+	assert.False(t, called)
+
+	// started task is not limited by queue deadline
+	ctx = WithQueueDeadline(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, r.ScheduleSync(ctx, tele_api.Priority_Default, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil
+	}))
+}
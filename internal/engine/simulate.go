@@ -0,0 +1,223 @@
+package engine
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const simContextKey = "run/engine-simulation"
+
+// SimModel replaces execution of actions matching Re, see Simulation.
+type SimModel struct {
+	Re       *regexp.Regexp
+	Duration time.Duration // fixed time
+	PerArg   time.Duration // added for each unit of first argument
+	Result   bool          // condition test result
+}
+
+// Simulation is dry run backend, device actions are replaced by timing models.
+// Matching actions are not executed, virtual clock advances by model time.
+// Sleep advances virtual clock too. Other actions are executed as usual.
+// Model is matched against action name, the one shown in trace, `mixer.move`.
+type Simulation struct {
+	models []SimModel
+}
+
+func NewSimulation(models ...SimModel) *Simulation {
+	return &Simulation{models: models}
+}
+
+// SimSpend is stock consumption, Spend in spend_rate units, Hw in hw_rate units.
+type SimSpend struct {
+	Spend float32
+	Hw    float32
+}
+
+// SimResult accumulates one simulated execution.
+type SimResult struct {
+	mu    sync.Mutex
+	clock *simClock
+	spend map[string]SimSpend
+}
+
+// Duration is virtual time of execution, parallel branches overlap.
+func (self *SimResult) Duration() time.Duration { return self.clock.now() }
+
+// Spend returns consumption by stock name.
+func (self *SimResult) Spend() map[string]SimSpend {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	result := make(map[string]SimSpend, len(self.spend))
+	for k, v := range self.spend {
+		result[k] = v
+	}
+	return result
+}
+
+type simClock struct {
+	mu sync.Mutex
+	at time.Duration
+}
+
+func (self *simClock) now() time.Duration {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.at
+}
+
+func (self *simClock) advance(d time.Duration) {
+	self.mu.Lock()
+	self.at += d
+	self.mu.Unlock()
+}
+
+// simRun is value in ctx, clock is per parallel branch.
+type simRun struct {
+	sim    *Simulation
+	result *SimResult
+	clock  *simClock
+}
+
+// WithSimulation returns ctx where Exec uses sim models.
+func WithSimulation(ctx context.Context, sim *Simulation) (context.Context, *SimResult) {
+	result := &SimResult{clock: &simClock{}, spend: make(map[string]SimSpend)}
+	run := &simRun{sim: sim, result: result, clock: result.clock}
+	return context.WithValue(ctx, simContextKey, run), result
+}
+
+func getSimRun(ctx context.Context) *simRun {
+	run, _ := ctx.Value(simContextKey).(*simRun)
+	return run
+}
+
+// SimulationSpend records stock consumption, no-op outside of simulation.
+func SimulationSpend(ctx context.Context, stock string, spend, hw float32) {
+	run := getSimRun(ctx)
+	if run == nil {
+		return
+	}
+	run.result.mu.Lock()
+	s := run.result.spend[stock]
+	s.Spend += spend
+	s.Hw += hw
+	run.result.spend[stock] = s
+	run.result.mu.Unlock()
+}
+
+func (self *Simulation) match(d Doer) (*SimModel, Args) {
+	name, _ := doerName(d)
+	for i := range self.models {
+		if self.models[i].Re.MatchString(name) {
+			return &self.models[i], simArgs(d)
+		}
+	}
+	return nil, Args{}
+}
+
+// simArgs finds applied arguments, of nested action if d is composite.
+func simArgs(d Doer) Args {
+	if _, args := doerName(d); args.Len() != 0 {
+		return args
+	}
+	if c, ok := d.(Composite); ok {
+		for _, child := range c.Children() {
+			if child == nil {
+				continue
+			}
+			if args := simArgs(child); args.Len() != 0 {
+				return args
+			}
+		}
+	}
+	return Args{}
+}
+
+// simDo returns true if d was handled by simulation instead of Do.
+func simDo(ctx context.Context, d Doer) bool {
+	run := getSimRun(ctx)
+	if run == nil {
+		return false
+	}
+	if sleep, ok := d.(Sleep); ok {
+		run.clock.advance(sleep.Duration)
+		return true
+	}
+	m, args := run.sim.match(d)
+	if m == nil {
+		return false
+	}
+	t := m.Duration
+	if len(args.Pos) != 0 {
+		t += m.PerArg * time.Duration(args.Pos[0])
+	}
+	run.clock.advance(t)
+	return true
+}
+
+// simTest returns modelled condition result, ok=false if cond is not modelled.
+func simTest(ctx context.Context, d Doer) (result bool, ok bool) {
+	run := getSimRun(ctx)
+	if run == nil {
+		return false, false
+	}
+	m, _ := run.sim.match(d)
+	if m == nil {
+		return false, false
+	}
+	return m.Result, true
+}
+
+// simFork gives each parallel branch own clock, nil outside of simulation.
+func simFork(ctx context.Context, n int) []context.Context {
+	run := getSimRun(ctx)
+	if run == nil {
+		return nil
+	}
+	now := run.clock.now()
+	ctxs := make([]context.Context, n)
+	for i := range ctxs {
+		branch := &simRun{sim: run.sim, result: run.result, clock: &simClock{at: now}}
+		ctxs[i] = context.WithValue(ctx, simContextKey, branch)
+	}
+	return ctxs
+}
+
+// simJoin advances clock to the longest branch.
+func simJoin(ctx context.Context, ctxs []context.Context) {
+	run := getSimRun(ctx)
+	if run == nil {
+		return
+	}
+	now := run.clock.now()
+	max := now
+	for _, c := range ctxs {
+		if t := getSimRun(c).clock.now(); t > max {
+			max = t
+		}
+	}
+	run.clock.advance(max - now)
+}
+
+// ParseSimModel accepts config strings, durations in Go format `1.5s`.
+func ParseSimModel(match, duration, perArg string, result bool) (SimModel, error) {
+	m := SimModel{Result: result}
+	var err error
+	if m.Re, err = regexp.Compile("^(?:" + match + ")$"); err != nil {
+		return m, errors.Annotatef(err, "model=%s", match)
+	}
+	if duration != "" {
+		if m.Duration, err = time.ParseDuration(duration); err != nil {
+			return m, errors.Annotatef(err, "model=%s duration", match)
+		}
+	}
+	if perArg != "" {
+		if m.PerArg, err = time.ParseDuration(perArg); err != nil {
+			return m, errors.Annotatef(err, "model=%s per_arg", match)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const spanContextKey = "run/engine-span"
+
+// Span is one executed Doer in trace tree.
+// Root span is created by ExecTrace without span in ctx, nested Exec add children.
+type Span struct {
+	Name     string
+	Args     string
+	Begin    time.Time
+	Duration time.Duration
+	Error    string
+	Children []*Span
+
+	mu sync.Mutex // guards Children, Duration, Error; par executes concurrently
+}
+
+func newSpan(d Doer) *Span {
+	name, args := doerName(d)
+	return &Span{Name: name, Args: args.String(), Begin: time.Now()}
+}
+
+// doerName returns action name without arguments and applied arguments.
+func doerName(d Doer) (string, Args) {
+	switch x := d.(type) {
+	case FuncArg:
+		if x.set {
+			return x.Name, Args{Pos: x.args}
+		}
+		return x.Name, Args{}
+	case CondArg:
+		if x.set {
+			return x.Name, Args{Pos: []Arg{x.arg}}
+		}
+		return x.Name, Args{}
+	}
+	return d.String(), Args{}
+}
+
+func getSpan(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanContextKey).(*Span)
+	return s
+}
+
+func (self *Span) add(child *Span) {
+	self.mu.Lock()
+	self.Children = append(self.Children, child)
+	self.mu.Unlock()
+}
+
+func (self *Span) finish(err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.Duration = time.Since(self.Begin)
+	if err != nil {
+		self.Error = err.Error()
+	}
+}
+
+// Failed returns deepest failed span, the step where error originated.
+func (self *Span) Failed() *Span {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.Error == "" {
+		return nil
+	}
+	for _, child := range self.Children {
+		if f := child.Failed(); f != nil {
+			return f
+		}
+	}
+	return self
+}
+
+// String formats tree one span per line, children indented.
+func (self *Span) String() string {
+	b := strings.Builder{}
+	self.format(&b, 0)
+	return b.String()
+}
+
+func (self *Span) format(b *strings.Builder, depth int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(self.Name)
+	if self.Args != "" {
+		fmt.Fprintf(b, "(%s)", self.Args)
+	}
+	fmt.Fprintf(b, " %v", self.Duration)
+	if self.Error != "" {
+		fmt.Fprintf(b, " err=%s", self.Error)
+	}
+	b.WriteByte('\n')
+	for _, child := range self.Children {
+		child.format(b, depth+1)
+	}
+}
+
+// SetTraceBuffer sets how many recent root spans Traces keeps.
+// Default 0 records only ExecTrace, nonzero traces every Exec.
+func (self *Engine) SetTraceBuffer(size int) {
+	self.trace.Lock()
+	self.trace.ring = make([]*Span, size)
+	self.trace.next = 0
+	self.trace.Unlock()
+}
+
+// Traces returns recent root spans, oldest first.
+func (self *Engine) Traces() []*Span {
+	self.trace.Lock()
+	defer self.trace.Unlock()
+	result := make([]*Span, 0, len(self.trace.ring))
+	for i := range self.trace.ring {
+		if s := self.trace.ring[(self.trace.next+i)%len(self.trace.ring)]; s != nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (self *Engine) traceEnabled() bool {
+	self.trace.Lock()
+	defer self.trace.Unlock()
+	return len(self.trace.ring) != 0
+}
+
+func (self *Engine) traceStore(s *Span) {
+	self.trace.Lock()
+	if len(self.trace.ring) != 0 {
+		self.trace.ring[self.trace.next] = s
+		self.trace.next = (self.trace.next + 1) % len(self.trace.ring)
+	}
+	self.trace.Unlock()
+}
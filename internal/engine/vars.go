@@ -0,0 +1,253 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const varsContextKey = "run/engine-vars"
+
+// Vars are scenario variables, `${name}` in action arguments.
+// Sources: engine defaults (SetVars), menu item (Let), UI options.
+type Vars map[string]Arg
+
+// WithVars returns ctx with vars added, overriding same names from parent ctx.
+func WithVars(ctx context.Context, vars Vars) context.Context {
+	parent := getVars(ctx)
+	merged := make(Vars, len(parent)+len(vars))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, varsContextKey, merged)
+}
+
+func getVars(ctx context.Context) Vars {
+	vars, _ := ctx.Value(varsContextKey).(Vars)
+	return vars
+}
+
+// SetVars sets default variables, ctx vars override them.
+func (self *Engine) SetVars(vars Vars) {
+	self.lk.Lock()
+	self.vars = vars
+	self.lk.Unlock()
+}
+
+func (self *Engine) currentVars(ctx context.Context) Vars {
+	self.lk.RLock()
+	defaults := self.vars
+	self.lk.RUnlock()
+	ctxVars := getVars(ctx)
+	if len(defaults) == 0 {
+		return ctxVars
+	}
+	result := make(Vars, len(defaults)+len(ctxVars))
+	for k, v := range defaults {
+		result[k] = v
+	}
+	for k, v := range ctxVars {
+		result[k] = v
+	}
+	return result
+}
+
+func hasVars(s string) bool { return strings.Contains(s, "${") }
+
+// Let executes D with Vars, menu item `vars { size = 2 }`.
+type Let struct {
+	Vars Vars
+	D    Doer
+}
+
+func (self *Let) Validate() error { return self.D.Validate() }
+func (self *Let) Do(ctx context.Context) error {
+	ctx = WithVars(ctx, self.Vars)
+	return GetGlobal(ctx).ExecPart(ctx, self.D)
+}
+func (self *Let) String() string   { return self.D.String() }
+func (self *Let) Children() []Doer { return []Doer{self.D} }
+
+var _ Composite = &Let{}
+
+// exprEnv is variable lookup for argument expressions.
+// check=true only validates syntax and variable names, every variable is 1,
+// value range and division by zero are unknown until real values, so not errors.
+type exprEnv struct {
+	vars  Vars
+	check bool
+}
+
+// EvalExpr computes integer expression `${water}*12/10`.
+// Operators + - * / % with usual precedence, parentheses, unary minus.
+func EvalExpr(s string, vars Vars) (Arg, error) {
+	return evalExpr(s, exprEnv{vars: vars})
+}
+
+func evalExpr(s string, env exprEnv) (Arg, error) {
+	p := exprParser{s: s, env: env}
+	v, err := p.expr()
+	if err == nil {
+		p.skipSpace()
+		if p.pos < len(p.s) {
+			err = errors.NotValidf("unexpected=%s", p.s[p.pos:])
+		}
+	}
+	if err != nil {
+		return 0, errors.Annotatef(err, "expression=%s", s)
+	}
+	return Arg(v), nil
+}
+
+type exprParser struct {
+	s   string
+	pos int
+	env exprEnv
+}
+
+func (self *exprParser) skipSpace() {
+	for self.pos < len(self.s) && isSpace(self.s[self.pos]) {
+		self.pos++
+	}
+}
+
+func (self *exprParser) peek() byte {
+	self.skipSpace()
+	if self.pos < len(self.s) {
+		return self.s[self.pos]
+	}
+	return 0
+}
+
+func (self *exprParser) expr() (int64, error) {
+	left, err := self.term()
+	for err == nil {
+		op := self.peek()
+		if op != '+' && op != '-' {
+			break
+		}
+		self.pos++
+		var right int64
+		if right, err = self.term(); err != nil {
+			break
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+		err = self.checkRange(left)
+	}
+	return left, err
+}
+
+func (self *exprParser) term() (int64, error) {
+	left, err := self.unary()
+	for err == nil {
+		op := self.peek()
+		if op != '*' && op != '/' && op != '%' {
+			break
+		}
+		self.pos++
+		var right int64
+		if right, err = self.unary(); err != nil {
+			break
+		}
+		switch {
+		case op == '*':
+			left *= right
+		case right == 0:
+			if !self.env.check {
+				return 0, errors.NotValidf("division by zero")
+			}
+		case op == '/':
+			left /= right
+		default:
+			left %= right
+		}
+		err = self.checkRange(left)
+	}
+	return left, err
+}
+
+func (self *exprParser) unary() (int64, error) {
+	if self.peek() == '-' {
+		self.pos++
+		v, err := self.unary()
+		return -v, err
+	}
+	return self.primary()
+}
+
+func (self *exprParser) primary() (int64, error) {
+	switch c := self.peek(); {
+	case c == '(':
+		self.pos++
+		v, err := self.expr()
+		if err != nil {
+			return 0, err
+		}
+		if self.peek() != ')' {
+			return 0, errors.NotValidf("missing )")
+		}
+		self.pos++
+		return v, nil
+
+	case c == '$':
+		if !strings.HasPrefix(self.s[self.pos:], "${") {
+			return 0, errors.NotValidf("variable syntax, expected ${name}")
+		}
+		end := strings.IndexByte(self.s[self.pos:], '}')
+		if end == -1 {
+			return 0, errors.NotValidf("variable missing }")
+		}
+		name := self.s[self.pos+2 : self.pos+end]
+		self.pos += end + 1
+		if name == "" {
+			return 0, errors.NotValidf("variable name empty")
+		}
+		if self.env.check {
+			return 1, nil
+		}
+		v, ok := self.env.vars[name]
+		if !ok {
+			return 0, errors.NotFoundf("variable=%s", name)
+		}
+		return int64(v), nil
+
+	case c >= '0' && c <= '9':
+		var v int64
+		for self.pos < len(self.s) && self.s[self.pos] >= '0' && self.s[self.pos] <= '9' {
+			v = v*10 + int64(self.s[self.pos]-'0')
+			if err := self.checkRange(v); err != nil {
+				return 0, err
+			}
+			self.pos++
+		}
+		return v, nil
+
+	case c == 0:
+		return 0, errors.NotValidf("unexpected end")
+	default:
+		return 0, errors.NotValidf("unexpected=%s", self.s[self.pos:])
+	}
+}
+
+func (self *exprParser) checkRange(v int64) error {
+	if self.env.check {
+		return nil
+	}
+	return checkArgRange(v)
+}
+
+func checkArgRange(v int64) error {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return errors.NotValidf("value=%d overflow", v)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/temoto/vender/hardware/input"
 	"github.com/temoto/vender/hardware/money"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/internal/state"
 	"github.com/temoto/vender/internal/types"
 	tele_api "github.com/temoto/vender/tele"
@@ -20,6 +21,7 @@ func (self *MoneySystem) SetAcceptMax(ctx context.Context, limit currency.Amount
 	errs := []error{
 		g.Engine.Exec(ctx, self.bill.AcceptMax(limit)),
 		g.Engine.Exec(ctx, self.coin.AcceptMax(limit)),
+		g.Engine.Exec(ctx, self.cashless.AcceptMax(limit)),
 	}
 	err := helpers.FoldErrors(errs)
 	if err != nil {
@@ -54,10 +56,13 @@ func (self *MoneySystem) AcceptCredit(ctx context.Context, maxPrice currency.Amo
 	}
 
 	alive := alive.NewAlive()
-	alive.Add(2)
+	alive.Add(3)
 	go self.bill.Run(ctx, alive, func(pi money.PollItem) bool {
 		switch pi.Status {
 		case money.StatusEscrow:
+			self.lk.Lock()
+			self.locked_store(ctx)
+			self.lk.Unlock()
 			if pi.DataCount == 1 {
 				if err := g.Engine.Exec(ctx, self.bill.EscrowAccept()); err != nil {
 					g.Error(errors.Annotatef(err, "money.bill escrow accept n=%s", currency.Amount(pi.DataNominal).FormatCtx(ctx)))
@@ -83,6 +88,8 @@ func (self *MoneySystem) AcceptCredit(ctx context.Context, maxPrice currency.Amo
 				self.locked_credit(creditCash|creditEscrow).FormatCtx(ctx),
 				self.locked_credit(creditAll).FormatCtx(ctx))
 			self.dirty += pi.Amount()
+			self.locked_store(ctx)
+			self.locked_emitInserted(ctx, "bill", pi.Amount())
 			alive.Stop()
 			if out != nil {
 				event := types.Event{Kind: types.EventMoneyCredit, Amount: pi.Amount()}
@@ -125,6 +132,8 @@ func (self *MoneySystem) AcceptCredit(ctx context.Context, maxPrice currency.Amo
 			_ = self.coin.TubeStatus()
 			_ = self.coin.ExpansionDiagStatus(nil)
 			self.dirty += pi.Amount()
+			self.locked_store(ctx)
+			self.locked_emitInserted(ctx, "coin", pi.Amount())
 			alive.Stop()
 			if out != nil {
 				event := types.Event{Kind: types.EventMoneyCredit, Amount: pi.Amount()}
@@ -138,6 +147,43 @@ func (self *MoneySystem) AcceptCredit(ctx context.Context, maxPrice currency.Amo
 		return false
 	})
 
+	go self.cashless.Run(ctx, alive, func(pi money.PollItem) bool {
+		self.lk.Lock()
+		defer self.lk.Unlock()
+
+		switch pi.Status {
+		case money.StatusCredit: // begin session
+			funds := currency.Amount(pi.DataNominal)
+			if funds > maxPrice {
+				funds = maxPrice
+			}
+			self.Log.Debugf("money.cashless session funds=%s credit=%s",
+				currency.Amount(pi.DataNominal).FormatCtx(ctx), funds.FormatCtx(ctx))
+			self.cashlessCredit = funds
+			self.cashlessSession = true
+			self.locked_emitInserted(ctx, "cashless", funds)
+			alive.Stop()
+			if out != nil {
+				event := types.Event{Kind: types.EventMoneyCredit, Amount: funds}
+				go func() { out <- event }()
+			}
+
+		case money.StatusDisabled: // reader ended session, i.e. card removed or timeout
+			self.Log.Debugf("money.cashless session end")
+			self.cashlessCredit = 0
+			self.cashlessSession = false
+			alive.Stop()
+			if out != nil {
+				event := types.Event{Kind: types.EventMoneyCredit}
+				go func() { out <- event }()
+			}
+
+		case money.StatusReturnRequest:
+			g.Hardware.Input.Emit(types.InputEvent{Source: input.MoneySourceTag, Key: input.MoneyKeyAbort})
+		}
+		return false
+	})
+
 	select {
 	case <-alive.WaitChan():
 		return nil
@@ -147,3 +193,12 @@ func (self *MoneySystem) AcceptCredit(ctx context.Context, maxPrice currency.Amo
 		return self.SetAcceptMax(ctx, 0)
 	}
 }
+
+func (self *MoneySystem) locked_emitInserted(ctx context.Context, source string, amount currency.Amount) {
+	g := state.GetGlobal(ctx)
+	g.Engine.Emit(ctx, engine.Event{
+		Name:    engine.EventMoneyInserted,
+		Subject: source,
+		Vars:    engine.Vars{"amount": engine.Arg(amount), "credit": engine.Arg(self.locked_credit(creditAll))},
+	})
+}
@@ -9,9 +9,12 @@ package money
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/hardware/mdb/cashless"
+	"github.com/temoto/vender/helpers"
 	"github.com/temoto/vender/internal/state"
 )
 
@@ -27,7 +30,8 @@ const (
 	creditCash    = creditFlag(1 << iota)
 	creditEscrow
 	creditGift
-	creditAll = creditCash | creditEscrow | creditGift
+	creditCashless
+	creditAll = creditCash | creditEscrow | creditGift | creditCashless
 )
 
 func (cf creditFlag) Contains(sub creditFlag) bool { return cf&sub != 0 }
@@ -45,6 +49,9 @@ func (self *MoneySystem) locked_credit(flag creditFlag) currency.Amount {
 	if flag.Contains(creditGift) {
 		result += self.giftCredit
 	}
+	if flag.Contains(creditCashless) {
+		result += self.cashlessCredit
+	}
 	return result
 }
 
@@ -69,13 +76,26 @@ func (self *MoneySystem) SetGiftCredit(ctx context.Context, value currency.Amoun
 	// copy both values to release lock ASAP
 	before, after := self.giftCredit, value
 	self.giftCredit = after
+	self.locked_store(ctx)
 	self.lk.Unlock()
 	self.Log.Infof("%s before=%s after=%s", tag, before.FormatCtx(ctx), after.FormatCtx(ctx))
 
 	// TODO notify ui-front
 }
 
-func (self *MoneySystem) WithdrawPrepare(ctx context.Context, amount currency.Amount) error {
+// Amount approved by cashless reader for current item, 0 if paid otherwise.
+func (self *MoneySystem) GetCashlessVend() currency.Amount {
+	self.lk.RLock()
+	c := self.cashlessVend
+	self.lk.RUnlock()
+	return c
+}
+
+// WithdrawPrepare secures payment for `item` menu code.
+// Cashless session pays what other credit lacks, reader may deny.
+// Lock is released while reader decides, credit is checked again after,
+// cash beyond what card did not cover is returned as change.
+func (self *MoneySystem) WithdrawPrepare(ctx context.Context, amount currency.Amount, item string) error {
 	const tag = "money.withdraw-prepare"
 	g := state.GetGlobal(ctx)
 
@@ -89,14 +109,40 @@ func (self *MoneySystem) WithdrawPrepare(ctx context.Context, amount currency.Am
 	}
 
 	change := currency.Amount(0)
-	// Don't give change from gift money.
-	if cash := self.locked_credit(creditCash | creditEscrow); cash > amount {
+	if other := self.locked_credit(creditCash | creditEscrow | creditGift); self.cashlessSession && other < amount {
+		itemNumber := cashlessItem(item)
+		self.lk.Unlock()
+		approved, err := self.cashless.VendRequest(ctx, amount-other, itemNumber)
+		self.lk.Lock()
+		if err != nil {
+			return errors.Annotate(err, tag)
+		}
+		// Session or other credit may have changed while waiting for reader.
+		other = self.locked_credit(creditCash | creditEscrow | creditGift)
+		if !self.cashlessSession || other+approved < amount {
+			self.Log.Errorf("%s cashless approved=%s other=%s session=%t, not enough for amount=%s",
+				tag, approved.FormatCtx(ctx), other.FormatCtx(ctx), self.cashlessSession, amount.FormatCtx(ctx))
+			if err := g.Engine.Exec(ctx, self.cashless.VendFailure()); err != nil {
+				self.Log.Error(errors.Annotate(err, tag))
+			}
+			return ErrNeedMoreMoney
+		}
+		self.cashlessVend = approved
+		self.cashlessItem = itemNumber
+		// Cash inserted while waiting for reader is not spent, give it back as change.
+		if cash, need := self.locked_credit(creditCash|creditEscrow), amount-approved; cash > need {
+			change = cash - need
+		}
+	} else if cash := self.locked_credit(creditCash | creditEscrow); cash > amount {
+		// Don't give change from gift money.
 		change = cash - amount
 	}
+	cashlessVend := self.cashlessVend
 
 	go func() {
 		self.lk.Lock()
 		defer self.lk.Unlock()
+		defer self.locked_store(ctx)
 
 		if err := self.locked_payout(ctx, change); err != nil {
 			err = errors.Annotate(err, tag)
@@ -114,7 +160,7 @@ func (self *MoneySystem) WithdrawPrepare(ctx context.Context, amount currency.Am
 			}
 		}
 
-		if self.dirty != amount {
+		if self.dirty+cashlessVend != amount {
 			self.Log.Errorf("%s CRITICAL amount=%s dirty=%s", tag, amount.FormatCtx(ctx), self.dirty.FormatCtx(ctx))
 		}
 	}()
@@ -128,21 +174,31 @@ func (self *MoneySystem) WithdrawCommit(ctx context.Context, amount currency.Amo
 
 	self.lk.Lock()
 	defer self.lk.Unlock()
+	defer self.locked_store(ctx)
 
 	self.Log.Debugf("%s amount=%s dirty=%s", tag, amount.FormatCtx(ctx), self.dirty.FormatCtx(ctx))
-	if self.dirty != amount {
+	if self.dirty+self.cashlessVend != amount {
 		self.Log.Errorf("%s CRITICAL amount=%s dirty=%s", tag, amount.FormatCtx(ctx), self.dirty.FormatCtx(ctx))
 	}
 	self.locked_zero()
 	return nil
 }
 
+// VendResult reports item outcome to cashless reader and completes session.
+// No-op without cashless session, failure makes reader refund approved amount.
+func (self *MoneySystem) VendResult(ctx context.Context, success bool) error {
+	self.lk.Lock()
+	defer self.lk.Unlock()
+	return errors.Annotate(self.locked_cashlessEnd(ctx, success), "money.vend-result")
+}
+
 // Release bill escrow + inserted coins
 // returns error *only* if unable to return all money
 func (self *MoneySystem) Abort(ctx context.Context) error {
 	const tag = "money-abort"
 	self.lk.Lock()
 	defer self.lk.Unlock()
+	defer self.locked_store(ctx)
 
 	cash := self.locked_credit(creditCash | creditEscrow)
 	self.Log.Debugf("%s cash=%s", tag, cash.FormatCtx(ctx))
@@ -152,6 +208,9 @@ func (self *MoneySystem) Abort(ctx context.Context) error {
 		state.GetGlobal(ctx).Tele.Error(err)
 		return err
 	}
+	if err := self.locked_cashlessEnd(ctx, false); err != nil {
+		state.GetGlobal(ctx).Tele.Error(errors.Annotate(err, tag))
+	}
 
 	if self.dirty != 0 {
 		self.Log.Errorf("%s CRITICAL (debt or code error) dirty=%s", tag, self.dirty.FormatCtx(ctx))
@@ -204,4 +263,32 @@ func (self *MoneySystem) locked_zero() {
 	self.billCredit.Clear()
 	self.coinCredit.Clear()
 	self.giftCredit = 0
+	self.cashlessCredit = 0
+}
+
+func (self *MoneySystem) locked_cashlessEnd(ctx context.Context, success bool) error {
+	g := state.GetGlobal(ctx)
+	errs := make([]error, 0, 2)
+	if self.cashlessVend != 0 {
+		d := self.cashless.VendFailure()
+		if success {
+			d = self.cashless.VendSuccess(self.cashlessItem)
+		}
+		errs = append(errs, g.Engine.Exec(ctx, d))
+		self.cashlessVend = 0
+	}
+	if self.cashlessSession {
+		errs = append(errs, g.Engine.Exec(ctx, self.cashless.SessionComplete()))
+		self.cashlessSession = false
+	}
+	self.cashlessCredit = 0
+	return helpers.FoldErrors(errs)
+}
+
+// cashlessItem converts menu code to reader item number.
+func cashlessItem(code string) uint16 {
+	if u, err := strconv.ParseUint(code, 10, 16); err == nil {
+		return uint16(u)
+	}
+	return cashless.ItemUnknown
 }
@@ -1,13 +1,20 @@
 package money
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/temoto/alive/v2"
+	"github.com/temoto/vender/currency"
 	"github.com/temoto/vender/hardware"
 	"github.com/temoto/vender/hardware/mdb"
+	"github.com/temoto/vender/hardware/mdb/coin"
+	"github.com/temoto/vender/hardware/money"
+	"github.com/temoto/vender/internal/engine"
 	state_new "github.com/temoto/vender/internal/state/new"
 )
 
@@ -60,7 +67,7 @@ func TestWithdrawGift(t *testing.T) {
 	gift := g.Config.ScaleU((rand.Uint32() % 100) + 3)
 	price := gift - g.Config.ScaleU(2)
 	ms.SetGiftCredit(ctx, gift)
-	require.NoError(t, ms.WithdrawPrepare(ctx, price))
+	require.NoError(t, ms.WithdrawPrepare(ctx, price, "1"))
 
 	// FIXME wait for change payout end
 	time.Sleep(200 * time.Millisecond)
@@ -70,3 +77,122 @@ func TestWithdrawGift(t *testing.T) {
 	t.Logf("gift=%s", ms.giftCredit.FormatCtx(ctx))
 	ms.lk.RUnlock()
 }
+
+func TestCashlessCoinDuringVend(t *testing.T) {
+	t.Parallel()
+
+	ctx, g := state_new.NewTestContext(t, "", `hardware{device "cashless" {}} money{scale=100 credit_max=10}`)
+	mock := mdb.MockFromContext(ctx)
+	defer mock.Close()
+	go mock.Expect([]mdb.MockR{
+		{"10", ""},
+		{"12", ""},
+		{"110001000000", "0101164301020500"},
+		{"1101ffff0000", ""},
+	})
+	require.NoError(t, hardware.Enum(ctx))
+	ms := MoneySystem{}
+	require.NoError(t, ms.Start(ctx))
+	coin := &coinGive{gave: make(chan currency.Amount, 1)}
+	ms.coin = coin
+
+	go mock.Expect([]mdb.MockR{
+		{"1401", ""},
+		{"12", "0303e8"},
+	})
+	require.NoError(t, ms.AcceptCredit(ctx, g.Config.ScaleU(3), nil, nil))
+
+	go func() {
+		mock.Expect([]mdb.MockR{
+			{"130000960003", ""},
+			{"12", ""},
+		})
+		// coin is credited while reader decides
+		ms.lk.Lock()
+		ms.dirty += 100
+		ms.lk.Unlock()
+		mock.Expect([]mdb.MockR{
+			{"12", "050096"},
+		})
+	}()
+	require.NoError(t, ms.WithdrawPrepare(ctx, 150, "3"))
+	assert.Equal(t, currency.Amount(150), ms.GetCashlessVend())
+	select {
+	case change := <-coin.gave:
+		assert.Equal(t, currency.Amount(100), change)
+	case <-time.After(time.Second):
+		t.Fatal("no change for coin inserted during vend request")
+	}
+
+	go mock.Expect([]mdb.MockR{
+		{"13020003", ""},
+		{"1304", "07"},
+	})
+	require.NoError(t, ms.VendResult(ctx, true))
+}
+
+// coinQuiet is coin.Stub without accept errors, to test other devices alone.
+type coinQuiet struct{ coin.Stub }
+
+func (coinQuiet) AcceptMax(currency.Amount) engine.Doer { return engine.Nothing{} }
+func (coinQuiet) Run(ctx context.Context, alive *alive.Alive, fun func(money.PollItem) bool) {
+	alive.Done()
+}
+
+// coinGive is coinQuiet dispensing any change.
+type coinGive struct {
+	coinQuiet
+	gave chan currency.Amount
+}
+
+func (self *coinGive) NewGive(amount currency.Amount, over bool, success *currency.NominalGroup) engine.Doer {
+	return engine.Func0{Name: "coin-give", F: func() error {
+		self.gave <- amount
+		return success.Add(currency.Nominal(amount), 1)
+	}}
+}
+
+func TestCashless(t *testing.T) {
+	t.Parallel()
+
+	ctx, g := state_new.NewTestContext(t, "", `hardware{device "cashless" {}} money{scale=100 credit_max=10}`)
+	mock := mdb.MockFromContext(ctx)
+	defer mock.Close()
+	go mock.Expect([]mdb.MockR{
+		{"10", ""},
+		{"12", ""},
+		{"110001000000", "0101164301020500"},
+		{"1101ffff0000", ""},
+	})
+	require.NoError(t, hardware.Enum(ctx))
+	ms := MoneySystem{}
+	require.NoError(t, ms.Start(ctx))
+	ms.coin = coinQuiet{}
+
+	// session funds are limited by max price
+	go mock.Expect([]mdb.MockR{
+		{"1401", ""},
+		{"12", "0303e8"},
+	})
+	require.NoError(t, ms.AcceptCredit(ctx, g.Config.ScaleU(3), nil, nil))
+	assert.Equal(t, g.Config.ScaleU(3), ms.Credit(ctx))
+
+	// approved less than requested
+	go mock.Expect([]mdb.MockR{
+		{"130000960003", "050064"},
+		{"1303", ""},
+	})
+	assert.Equal(t, ErrNeedMoreMoney, ms.WithdrawPrepare(ctx, 150, "3"))
+	assert.Equal(t, currency.Amount(0), ms.GetCashlessVend())
+
+	go mock.Expect([]mdb.MockR{
+		{"130000960003", "050096"},
+		{"13020003", ""},
+		{"1304", "07"},
+	})
+	require.NoError(t, ms.WithdrawPrepare(ctx, 150, "3"))
+	assert.Equal(t, currency.Amount(150), ms.GetCashlessVend())
+	require.NoError(t, ms.VendResult(ctx, true))
+	assert.Equal(t, currency.Amount(0), ms.Credit(ctx))
+	assert.Equal(t, currency.Amount(0), ms.GetCashlessVend())
+}
@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: money_state.proto
+
+package money
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Customer credit, stored on every change to survive power loss.
+type State struct {
+	Dirty                uint32            `protobuf:"varint,1,opt,name=dirty,proto3" json:"dirty,omitempty"`
+	GiftCredit           uint32            `protobuf:"varint,2,opt,name=gift_credit,json=giftCredit,proto3" json:"gift_credit,omitempty"`
+	BillCredit           map[uint32]uint32 `protobuf:"bytes,3,rep,name=bill_credit,json=billCredit,proto3" json:"bill_credit,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	CoinCredit           map[uint32]uint32 `protobuf:"bytes,4,rep,name=coin_credit,json=coinCredit,proto3" json:"coin_credit,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Escrow               uint32            `protobuf:"varint,5,opt,name=escrow,proto3" json:"escrow,omitempty"`
+	Time                 int64             `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *State) Reset()         { *m = State{} }
+func (m *State) String() string { return proto.CompactTextString(m) }
+func (*State) ProtoMessage()    {}
+func (*State) Descriptor() ([]byte, []int) {
+	return fileDescriptor_money_state_ac2a1eb090233535, []int{0}
+}
+func (m *State) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_State.Unmarshal(m, b)
+}
+func (m *State) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_State.Marshal(b, m, deterministic)
+}
+func (dst *State) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_State.Merge(dst, src)
+}
+func (m *State) XXX_Size() int {
+	return xxx_messageInfo_State.Size(m)
+}
+func (m *State) XXX_DiscardUnknown() {
+	xxx_messageInfo_State.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_State proto.InternalMessageInfo
+
+func (m *State) GetDirty() uint32 {
+	if m != nil {
+		return m.Dirty
+	}
+	return 0
+}
+
+func (m *State) GetGiftCredit() uint32 {
+	if m != nil {
+		return m.GiftCredit
+	}
+	return 0
+}
+
+func (m *State) GetBillCredit() map[uint32]uint32 {
+	if m != nil {
+		return m.BillCredit
+	}
+	return nil
+}
+
+func (m *State) GetCoinCredit() map[uint32]uint32 {
+	if m != nil {
+		return m.CoinCredit
+	}
+	return nil
+}
+
+func (m *State) GetEscrow() uint32 {
+	if m != nil {
+		return m.Escrow
+	}
+	return 0
+}
+
+func (m *State) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*State)(nil), "money.State")
+	proto.RegisterMapType((map[uint32]uint32)(nil), "money.State.BillCreditEntry")
+	proto.RegisterMapType((map[uint32]uint32)(nil), "money.State.CoinCreditEntry")
+}
+
+func init() { proto.RegisterFile("money_state.proto", fileDescriptor_money_state_ac2a1eb090233535) }
+
+var fileDescriptor_money_state_ac2a1eb090233535 = []byte{
+	// 231 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcc, 0xcd, 0xcf, 0x4b,
+	0xad, 0x8c, 0x2f, 0x2e, 0x49, 0x2c, 0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05,
+	0x0b, 0x29, 0x3d, 0x63, 0xe2, 0x62, 0x0d, 0x06, 0x09, 0x0b, 0x89, 0x70, 0xb1, 0xa6, 0x64, 0x16,
+	0x95, 0x54, 0x4a, 0x30, 0x2a, 0x30, 0x6a, 0xf0, 0x06, 0x41, 0x38, 0x42, 0xf2, 0x5c, 0xdc, 0xe9,
+	0x99, 0x69, 0x25, 0xf1, 0xc9, 0x45, 0xa9, 0x29, 0x99, 0x25, 0x12, 0x4c, 0x60, 0x39, 0x2e, 0x90,
+	0x90, 0x33, 0x58, 0x44, 0xc8, 0x96, 0x8b, 0x3b, 0x29, 0x33, 0x27, 0x07, 0xa6, 0x80, 0x59, 0x81,
+	0x59, 0x83, 0xdb, 0x48, 0x46, 0x0f, 0x6c, 0xba, 0x1e, 0xd8, 0x64, 0x3d, 0xa7, 0xcc, 0x9c, 0x1c,
+	0x88, 0x6a, 0xd7, 0xbc, 0x92, 0xa2, 0xca, 0x20, 0xae, 0x24, 0xb8, 0x00, 0x48, 0x7b, 0x72, 0x7e,
+	0x66, 0x1e, 0x4c, 0x3b, 0x0b, 0x16, 0xed, 0xce, 0xf9, 0x99, 0x79, 0x28, 0xda, 0x93, 0xe1, 0x02,
+	0x42, 0x62, 0x5c, 0x6c, 0xa9, 0xc5, 0xc9, 0x45, 0xf9, 0xe5, 0x12, 0xac, 0x60, 0x97, 0x41, 0x79,
+	0x42, 0x42, 0x5c, 0x2c, 0x25, 0x99, 0xb9, 0xa9, 0x12, 0x6c, 0x0a, 0x8c, 0x1a, 0xcc, 0x41, 0x60,
+	0xb6, 0x94, 0x2d, 0x17, 0x3f, 0x9a, 0x4b, 0x84, 0x04, 0xb8, 0x98, 0xb3, 0x53, 0x61, 0x3e, 0x06,
+	0x31, 0x41, 0xa1, 0x50, 0x96, 0x98, 0x53, 0x9a, 0x0a, 0xf5, 0x29, 0x84, 0x63, 0xc5, 0x64, 0xc1,
+	0x08, 0xd2, 0x8e, 0xe6, 0x12, 0x52, 0xb4, 0x27, 0xb1, 0x81, 0x83, 0xdd, 0x18, 0x30, 0x00, 0xaa,
+	0xce, 0xd4, 0x71, 0x8b, 0x01, 0x00, 0x00,
+}
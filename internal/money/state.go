@@ -0,0 +1,84 @@
+package money
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/juju/errors"
+	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/internal/state"
+	"github.com/temoto/vender/internal/state/persist"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+//go:generate protoc --go_out=./ money_state.proto
+
+// creditState is last stored credit snapshot.
+type creditState struct{ State }
+
+func (self *creditState) MarshalBinary() ([]byte, error) { return proto.Marshal(&self.State) }
+func (self *creditState) UnmarshalBinary(b []byte) error { return proto.Unmarshal(b, &self.State) }
+
+var _ persist.Stater = &creditState{}
+
+// locked_store saves credit if changed. Cashless session is not stored, reader cancels it on reset.
+func (self *MoneySystem) locked_store(ctx context.Context) {
+	next := State{
+		Dirty:      uint32(self.dirty),
+		GiftCredit: uint32(self.giftCredit),
+		BillCredit: make(map[uint32]uint32),
+		CoinCredit: make(map[uint32]uint32),
+		Escrow:     uint32(self.bill.EscrowAmount()),
+		Time:       self.state.Time,
+	}
+	self.billCredit.ToMapUint32(next.BillCredit)
+	self.coinCredit.ToMapUint32(next.CoinCredit)
+	if proto.Equal(&next, &self.state.State) {
+		return
+	}
+	next.Time = time.Now().UnixNano()
+	self.state.State = next
+	if err := self.persist.Store(); err != nil {
+		state.GetGlobal(ctx).Error(errors.Annotate(err, "CRITICAL money credit"))
+	}
+}
+
+// locked_restore handles credit left by power loss or crash.
+// Escrow bill is never restored, validator returns it on reset.
+func (self *MoneySystem) locked_restore(ctx context.Context) {
+	const tag = "money.restore"
+	g := state.GetGlobal(ctx)
+	s := &self.state.State
+	if s.Dirty == 0 && s.GiftCredit == 0 && s.Escrow == 0 {
+		return
+	}
+	refund := &tele_api.Telemetry_Refund{
+		Credit:  s.Dirty,
+		Gift:    s.GiftCredit,
+		Escrow:  s.Escrow,
+		Bills:   s.BillCredit,
+		Coins:   s.CoinCredit,
+		Time:    s.Time,
+		Offered: g.Config.Money.RestoreCredit,
+	}
+	self.Log.Errorf("%s credit=%s gift=%s escrow=%s changed=%s offered=%t", tag,
+		currency.Amount(s.Dirty).FormatCtx(ctx), currency.Amount(s.GiftCredit).FormatCtx(ctx),
+		currency.Amount(s.Escrow).FormatCtx(ctx), time.Unix(0, s.Time).Format(time.RFC3339), refund.Offered)
+	if refund.Offered {
+		self.dirty = currency.Amount(s.Dirty)
+		self.giftCredit = currency.Amount(s.GiftCredit)
+		for n, c := range s.BillCredit {
+			if err := self.billCredit.Add(currency.Nominal(n), uint(c)); err != nil {
+				g.Error(errors.Annotatef(err, "%s bill n=%d c=%d", tag, n, c))
+			}
+		}
+		for n, c := range s.CoinCredit {
+			if err := self.coinCredit.Add(currency.Nominal(n), uint(c)); err != nil {
+				g.Error(errors.Annotatef(err, "%s coin n=%d c=%d", tag, n, c))
+			}
+		}
+	}
+	g.Tele.Refund(refund)
+	self.locked_store(ctx)
+}
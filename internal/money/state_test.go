@@ -0,0 +1,63 @@
+package money
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/vender/currency"
+	state_new "github.com/temoto/vender/internal/state/new"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+type teleRefund struct {
+	tele_api.Teler
+	refunds []*tele_api.Telemetry_Refund
+}
+
+func (self *teleRefund) Refund(r *tele_api.Telemetry_Refund) { self.refunds = append(self.refunds, r) }
+
+func TestPersistCredit(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	start := func(restore bool) (context.Context, *MoneySystem, *teleRefund) {
+		ctx, g := state_new.NewTestContext(t, "", fmt.Sprintf(
+			`money{scale=100 persist=true restore_credit=%t} persist{root="%s"}`, restore, root))
+		tele := &teleRefund{Teler: g.Tele}
+		g.Tele = tele
+		ms := &MoneySystem{}
+		require.NoError(t, ms.Start(ctx))
+		return ctx, ms, tele
+	}
+
+	ctx, ms, tele := start(true)
+	assert.Empty(t, tele.refunds)
+	ms.SetGiftCredit(ctx, 300)
+	ms.lk.Lock()
+	ms.dirty += 200
+	ms.locked_store(ctx)
+	ms.lk.Unlock()
+
+	// power loss, credit is offered back
+	ctx, ms, tele = start(true)
+	assert.Equal(t, currency.Amount(500), ms.Credit(ctx))
+	require.Len(t, tele.refunds, 1)
+	assert.Equal(t, uint32(200), tele.refunds[0].Credit)
+	assert.Equal(t, uint32(300), tele.refunds[0].Gift)
+	assert.True(t, tele.refunds[0].Offered)
+	assert.NotZero(t, tele.refunds[0].Time)
+
+	// power loss again, only pending refund
+	ctx, ms, tele = start(false)
+	assert.Equal(t, currency.Amount(0), ms.Credit(ctx))
+	require.Len(t, tele.refunds, 1)
+	assert.Equal(t, uint32(200), tele.refunds[0].Credit)
+	assert.False(t, tele.refunds[0].Offered)
+
+	// reported refund is not repeated
+	_, _, tele = start(true)
+	assert.Empty(t, tele.refunds)
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/juju/errors"
 	"github.com/temoto/vender/currency"
 	"github.com/temoto/vender/hardware/mdb/bill"
+	"github.com/temoto/vender/hardware/mdb/cashless"
 	"github.com/temoto/vender/hardware/mdb/coin"
 	"github.com/temoto/vender/helpers"
 	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/internal/state"
+	"github.com/temoto/vender/internal/state/persist"
 	"github.com/temoto/vender/log2"
 	tele_api "github.com/temoto/vender/tele"
 )
@@ -30,7 +32,16 @@ type MoneySystem struct { //nolint:maligned
 	coinCashbox currency.NominalGroup
 	coinCredit  currency.NominalGroup
 
+	cashless        cashless.Cashlesser
+	cashlessCredit  currency.Amount // session funds, limited by max price
+	cashlessSession bool
+	cashlessVend    currency.Amount // approved by reader for current item
+	cashlessItem    uint16
+
 	giftCredit currency.Amount
+
+	persist persist.Persist
+	state   creditState
 }
 
 func GetGlobal(ctx context.Context) *MoneySystem {
@@ -47,9 +58,11 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 
 	const devNameBill = "bill"
 	const devNameCoin = "coin"
+	const devNameCashless = "cashless"
 	self.bill = bill.Stub{}
 	self.coin = coin.Stub{}
-	errs := make([]error, 0, 2)
+	self.cashless = cashless.Stub{}
+	errs := make([]error, 0, 3)
 	if dev, err := g.GetDevice(devNameBill); err == nil {
 		self.bill = dev.(bill.Biller)
 	} else if errors.IsNotFound(err) {
@@ -64,6 +77,13 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 	} else {
 		errs = append(errs, errors.Annotatef(err, "device=%s", devNameCoin))
 	}
+	if dev, err := g.GetDevice(devNameCashless); err == nil {
+		self.cashless = dev.(cashless.Cashlesser)
+	} else if errors.IsNotFound(err) {
+		self.Log.Debugf("device=%s is not enabled in config", devNameCashless)
+	} else {
+		errs = append(errs, errors.Annotatef(err, "device=%s", devNameCashless))
+	}
 	if e := helpers.FoldErrors(errs); e != nil {
 		return e
 	}
@@ -73,6 +93,16 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 	self.coinCashbox.SetValid(self.coin.SupportedNominals())
 	self.coinCredit.SetValid(self.coin.SupportedNominals())
 
+	if err := self.persist.Init("money", &self.state, g.Config.Persist.Root, g.Config.Money.Persist, g.Log); err != nil {
+		return errors.Annotate(err, "money persist")
+	}
+	if err := self.persist.Load(); err != nil {
+		// broken state must not stop sales, operator learns from telemetry
+		g.Error(errors.Annotate(err, "CRITICAL money credit"))
+	} else {
+		self.locked_restore(ctx)
+	}
+
 	g.Engine.RegisterNewFunc(
 		"money.cashbox_zero",
 		func(ctx context.Context) error {
@@ -82,6 +112,7 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			self.coinCashbox.Clear()
 			return nil
 		},
+		engine.Meta{Description: "reset cashbox counters after collection", Device: "money"},
 	)
 	g.Engine.RegisterNewFunc(
 		"money.consume!",
@@ -90,6 +121,7 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			err := self.WithdrawCommit(ctx, credit)
 			return errors.Annotatef(err, "consume=%s", credit.FormatCtx(ctx))
 		},
+		engine.Meta{Description: "take all credit", Device: "money"},
 	)
 	g.Engine.RegisterNewFunc(
 		"money.commit",
@@ -98,8 +130,10 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			err := self.WithdrawCommit(ctx, curPrice)
 			return errors.Annotatef(err, "curPrice=%s", curPrice.FormatCtx(ctx))
 		},
+		engine.Meta{Description: "take price of current item from credit", Device: "money", Safe: true},
 	)
-	g.Engine.RegisterNewFunc("money.abort", self.Abort)
+	g.Engine.RegisterNewFunc("money.abort", self.Abort,
+		engine.Meta{Description: "return credit to customer", Device: "money", Safe: true})
 
 	doAccept := engine.FuncArg{
 		Name: "money.accept(?)",
@@ -108,7 +142,8 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			return nil
 		},
 	}
-	g.Engine.Register(doAccept.Name, doAccept)
+	g.Engine.Register(doAccept.Name, doAccept,
+		engine.Meta{Description: "add credit without payment", Device: "money", Params: []engine.Param{{Name: "amount", Unit: "currency"}}})
 
 	doGive := engine.FuncArg{
 		Name: "money.give(?)",
@@ -119,8 +154,9 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			self.Log.Infof("dispensed=%s", dispensed.String())
 			return err
 		}}
-	g.Engine.Register(doGive.Name, doGive)
-	g.Engine.Register("money.dispense(?)", doGive) // FIXME remove deprecated
+	giveMeta := engine.Meta{Description: "dispense coins", Device: "money", Params: []engine.Param{{Name: "amount", Unit: "currency"}}}
+	g.Engine.Register(doGive.Name, doGive, giveMeta)
+	g.Engine.Register("money.dispense(?)", doGive, giveMeta) // FIXME remove deprecated
 
 	doSetGiftCredit := engine.FuncArg{
 		Name: "money.set_gift_credit(?)",
@@ -130,7 +166,25 @@ func (self *MoneySystem) Start(ctx context.Context) error {
 			return nil
 		},
 	}
-	g.Engine.Register(doSetGiftCredit.Name, doSetGiftCredit)
+	g.Engine.Register(doSetGiftCredit.Name, doSetGiftCredit,
+		engine.Meta{Description: "set credit paid by gift", Device: "money", Params: []engine.Param{{Name: "amount", Unit: "currency"}}})
+
+	doPriceGE := engine.CondArg{
+		Name: "money.price_ge(?)",
+		F: func(ctx context.Context, arg engine.Arg) (bool, error) {
+			return GetCurrentPrice(ctx) >= g.Config.ScaleU(uint32(arg)), nil
+		},
+	}
+	g.Engine.Register(doPriceGE.Name, doPriceGE,
+		engine.Meta{Description: "current item price >= amount", Device: "money", Params: []engine.Param{{Name: "amount", Unit: "currency"}}, Safe: true})
+	doPriceLT := engine.CondArg{
+		Name: "money.price_lt(?)",
+		F: func(ctx context.Context, arg engine.Arg) (bool, error) {
+			return GetCurrentPrice(ctx) < g.Config.ScaleU(uint32(arg)), nil
+		},
+	}
+	g.Engine.Register(doPriceLT.Name, doPriceLT,
+		engine.Meta{Description: "current item price < amount", Device: "money", Params: []engine.Param{{Name: "amount", Unit: "currency"}}, Safe: true})
 
 	return nil
 }
@@ -142,6 +196,7 @@ func (self *MoneySystem) Stop(ctx context.Context) error {
 	errs = append(errs, self.Abort(ctx))
 	errs = append(errs, g.Engine.Exec(ctx, self.bill.AcceptMax(0)))
 	errs = append(errs, g.Engine.Exec(ctx, self.coin.AcceptMax(0)))
+	errs = append(errs, g.Engine.Exec(ctx, self.cashless.AcceptMax(0)))
 	return errors.Annotate(helpers.FoldErrors(errs), tag)
 }
 
@@ -5,12 +5,15 @@ import (
 	"sync"
 
 	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
 	"github.com/juju/errors"
 	"github.com/temoto/vender/currency"
 	"github.com/temoto/vender/hardware/hd44780"
 	mdb_config "github.com/temoto/vender/hardware/mdb/config"
 	evend_config "github.com/temoto/vender/hardware/mdb/evend/config"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	engine_config "github.com/temoto/vender/internal/engine/config"
 	ui_config "github.com/temoto/vender/internal/ui/config"
 	"github.com/temoto/vender/log2"
@@ -68,9 +71,11 @@ type Config struct {
 
 	Engine engine_config.Config
 	Money  struct {
-		Scale                int `hcl:"scale"`
-		CreditMax            int `hcl:"credit_max"`
-		ChangeOverCompensate int `hcl:"change_over_compensate"`
+		Scale                int  `hcl:"scale"`
+		CreditMax            int  `hcl:"credit_max"`
+		ChangeOverCompensate int  `hcl:"change_over_compensate"`
+		Persist              bool `hcl:"persist"`        // store credit on every change
+		RestoreCredit        bool `hcl:"restore_credit"` // offer persisted credit to customer at boot, otherwise only report refund
 	}
 	Persist struct {
 		Root string `hcl:"root"`
@@ -120,12 +125,16 @@ func (c *Config) read(log *log2.Log, fs FullReader, source ConfigSource, errs *[
 		return
 	}
 
+	before := c.scenarioCount()
 	err = hcl.Unmarshal(bs, c)
 	if err != nil {
 		err = errors.Annotatef(err, "config unmarshal source=%s content='%s'", source.Name, string(bs))
 		*errs = append(*errs, err)
 		return
 	}
+	if f, err := hcl.ParseBytes(bs); err == nil {
+		c.scenarioPos(source.Name, f, before)
+	}
 
 	var includes []ConfigSource
 	includes, c.XXX_Include = c.XXX_Include, nil
@@ -167,3 +176,89 @@ func MustReadConfig(log *log2.Log, fs FullReader, names ...string) *Config {
 	}
 	return c
 }
+
+type scenarioCount struct{ aliases, items, jobs, hooks int }
+
+func (c *Config) scenarioCount() scenarioCount {
+	return scenarioCount{
+		aliases: len(c.Engine.Aliases),
+		items:   len(c.Engine.Menu.Items),
+		jobs:    len(c.Engine.Jobs),
+		hooks:   len(c.Engine.Hooks),
+	}
+}
+
+// scenarioPos remembers where scenarios appended by last Unmarshal are written, for parse errors.
+// Positions are skipped if syntax tree does not match decoded blocks.
+func (c *Config) scenarioPos(file string, f *ast.File, before scenarioCount) {
+	list, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return
+	}
+	if ps := hclBlockPos(file, list, []string{"engine", "alias"}, "scenario"); len(ps) == len(c.Engine.Aliases)-before.aliases {
+		undos := hclBlockPos(file, list, []string{"engine", "alias"}, "undo")
+		for i := range ps {
+			x := &c.Engine.Aliases[before.aliases+i]
+			x.ScenarioPos, x.UndoPos = ps[i], undos[i]
+		}
+	}
+	if ps := hclBlockPos(file, list, []string{"engine", "menu", "item"}, "scenario"); len(ps) == len(c.Engine.Menu.Items)-before.items {
+		for i, p := range ps {
+			c.Engine.Menu.Items[before.items+i].ScenarioPos = p
+		}
+	}
+	if ps := hclBlockPos(file, list, []string{"engine", "job"}, "scenario"); len(ps) == len(c.Engine.Jobs)-before.jobs {
+		for i, p := range ps {
+			c.Engine.Jobs[before.jobs+i].ScenarioPos = p
+		}
+	}
+	if ps := hclBlockPos(file, list, []string{"engine", "hook"}, "scenario"); len(ps) == len(c.Engine.Hooks)-before.hooks {
+		for i, p := range ps {
+			c.Engine.Hooks[before.hooks+i].ScenarioPos = p
+		}
+	}
+}
+
+// hclBlockPos returns position of string attribute in each block at path, in file order.
+// `engine { menu { item "1" {} } }` and `engine menu item "1" {}` are same path.
+// Zero Pos means block has no such attribute.
+func hclBlockPos(file string, list *ast.ObjectList, path []string, attr string) []engine.Pos {
+	result := []engine.Pos{}
+	for _, item := range list.Items {
+		rest, keys := path, item.Keys
+		for len(rest) != 0 && len(keys) != 0 && hclKey(keys[0]) == rest[0] {
+			rest, keys = rest[1:], keys[1:]
+		}
+		obj, ok := item.Val.(*ast.ObjectType)
+		switch {
+		case !ok:
+		case len(rest) != 0 && len(keys) == 0:
+			result = append(result, hclBlockPos(file, obj.List, rest, attr)...)
+		case len(rest) == 0:
+			var pos engine.Pos
+			for _, x := range obj.List.Filter(attr).Items {
+				if lit, ok := x.Val.(*ast.LiteralType); ok && len(x.Keys) == 0 {
+					pos = hclLiteralPos(file, lit.Token)
+				}
+			}
+			result = append(result, pos)
+		}
+	}
+	return result
+}
+
+func hclKey(k *ast.ObjectKey) string {
+	s, _ := k.Token.Value().(string)
+	return s
+}
+
+// hclLiteralPos returns position of first char of string value.
+func hclLiteralPos(file string, t token.Token) engine.Pos {
+	switch t.Type {
+	case token.STRING:
+		return engine.Pos{File: file, Line: t.Pos.Line, Col: t.Pos.Column + 1}
+	case token.HEREDOC:
+		return engine.Pos{File: file, Line: t.Pos.Line + 1, Col: 1}
+	}
+	return engine.Pos{}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/temoto/alive/v2"
 	"github.com/temoto/vender/helpers"
 	"github.com/temoto/vender/internal/engine"
+	engine_config "github.com/temoto/vender/internal/engine/config"
 	"github.com/temoto/vender/internal/engine/inventory"
 	"github.com/temoto/vender/log2"
 	tele_api "github.com/temoto/vender/tele"
@@ -149,6 +150,49 @@ engine { inventory {
 				assert.Equal(t, float32(13-4*3), stock.Value())
 			}, ""},
 
+		{"inventory-hw-table", `
+engine { inventory {
+	stock "water" { hw_table = [[10, 7], [100, 65.5]] }
+}}`,
+			func(t testing.TB, ctx context.Context) {
+				g := GetGlobal(ctx)
+				stock, err := g.Inventory.Get("water")
+				require.NoError(t, err)
+				assert.Equal(t, [][]float32{{10, 7}, {100, 65.5}}, g.Config.Engine.Inventory.Stocks[0].HwTable)
+				assert.Equal(t, float32(36), stock.TranslateHw(55))
+			}, ""},
+
+		{"inventory-migrate", `
+engine { inventory {
+	stock "milk" {}
+	migrate "milk_a" { to = "milk" }
+	migrate "milk_b" { to = "milk" }
+}}`,
+			func(t testing.TB, ctx context.Context) {
+				g := GetGlobal(ctx)
+				assert.Equal(t, []engine_config.StockMigrate{{From: "milk_a", To: "milk"}, {From: "milk_b", To: "milk"}}, g.Config.Engine.Inventory.Migrate)
+			}, ""},
+
+		{"scenario-pos", `
+engine {
+	alias "warm" {
+		scenario = <<EOF
+sleep(1ms)
+EOF
+	}
+	menu { item "1" { name = "first" scenario = "warm" } }
+}`,
+			func(t testing.TB, ctx context.Context) {
+				g := GetGlobal(ctx)
+				assert.Equal(t, engine.Pos{File: "test-inline", Line: 5, Col: 1}, g.Config.Engine.Aliases[0].ScenarioPos)
+				assert.Equal(t, engine.Pos{}, g.Config.Engine.Aliases[0].UndoPos)
+				assert.Equal(t, engine.Pos{File: "test-inline", Line: 8, Col: 47}, g.Config.Engine.Menu.Items[0].ScenarioPos)
+			}, ""},
+		{"error-scenario-pos", `
+engine { menu {
+	item "1" { name = "first" scenario = "a end" }
+} }`, nil, "test-inline:3:42 scenario=first word=end: unexpected"},
+
 		{"error-syntax", `hello`, nil, "key 'hello' expected start of object"},
 		{"error-include-loop", `include "include-loop" {}`, nil, "config include loop: from=include-loop include=include-loop"},
 	}
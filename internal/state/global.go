@@ -29,12 +29,15 @@ type Global struct {
 	Hardware     hardware // hardware.go
 	Inventory    *inventory.Inventory
 	Log          *log2.Log
+	Runner       *engine.Run // task queue for ScheduleSync, except IdleUser
 	Tele         tele_api.Teler
 	// TODO UI           types.UIer
 
 	XXX_money atomic.Value // *money.MoneySystem crutch to import cycle
 	XXX_uier  atomic.Value // UIer crutch to import/init cycle
 
+	jobs []*job // job.go
+
 	_copy_guard sync.Mutex //nolint:unused
 }
 
@@ -138,19 +141,12 @@ func (g *Global) Fatal(err error, args ...interface{}) {
 }
 
 func (g *Global) ScheduleSync(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc) error {
-	// TODO task := g.Schedule(ctx, priority, fun)
-	// return task.wait()
-
 	g.Alive.Add(1)
 	defer g.Alive.Done()
 
 	switch priority {
-	case tele_api.Priority_Default, tele_api.Priority_Now:
-		return fun(ctx)
-
-	case tele_api.Priority_IdleEngine:
-		// TODO return g.Engine.Schedule(ctx, priority, fun)
-		return fun(ctx)
+	case tele_api.Priority_Default, tele_api.Priority_Now, tele_api.Priority_IdleEngine:
+		return g.Runner.ScheduleSync(ctx, priority, fun)
 
 	case tele_api.Priority_IdleUser:
 		return g.UI().ScheduleSync(ctx, priority, fun)
@@ -166,6 +162,7 @@ func (g *Global) Stop() {
 
 func (g *Global) StopWait(timeout time.Duration) bool {
 	g.Alive.Stop()
+	defer g.Inventory.Stop()
 	select {
 	case <-g.Alive.WaitChan():
 		return true
@@ -196,29 +193,65 @@ func (g *Global) initDisplay() error {
 func (g *Global) initEngine() error {
 	errs := make([]error, 0)
 
-	for _, x := range g.Config.Engine.Aliases {
+	for i := range g.Config.Engine.Aliases {
+		x := &g.Config.Engine.Aliases[i]
 		var err error
-		x.Doer, err = g.Engine.ParseText(x.Name, x.Scenario)
+		x.Doer, err = g.Engine.ParseSource(engine.Source{Tag: x.Name, Text: x.Scenario, Pos: x.ScenarioPos})
+		if err == nil && x.Undo != "" {
+			var undo engine.Doer
+			undo, err = g.Engine.ParseSource(engine.Source{Tag: x.Name + "/undo", Text: x.Undo, Pos: x.UndoPos})
+			x.Doer = &engine.Compensate{D: x.Doer, Undo: undo}
+		}
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
 		// g.Log.Debugf("config.engine.alias name=%s scenario=%s", x.Name, x.Scenario)
-		g.Engine.Register(x.Name, x.Doer)
+		g.Engine.Register(x.Name, x.Doer, engine.Meta{Description: "alias: " + x.Scenario, Safe: true})
 	}
 
+	g.Engine.SetVars(configVars(g.Config.Engine.Vars))
+
 	for _, x := range g.Config.Engine.Menu.Items {
 		var err error
 		x.Price = g.Config.ScaleI(x.XXX_Price)
-		x.Doer, err = g.Engine.ParseText(x.Name, x.Scenario)
+		x.Doer, err = g.Engine.ParseSource(engine.Source{Tag: x.Name, Text: x.Scenario, Pos: x.ScenarioPos})
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, errors.Annotatef(err, "menu.%s", x.Code))
 			continue
 		}
+		if len(x.Vars) != 0 {
+			x.Doer = &engine.Let{Vars: configVars(x.Vars), D: x.Doer}
+		}
 		// g.Log.Debugf("config.engine.menu %s pxxx=%d ps=%d", x.String(), x.XXX_Price, x.Price)
-		g.Engine.Register("menu."+x.Code, x.Doer)
+		g.Engine.Register("menu."+x.Code, x.Doer, engine.Meta{Description: "menu item: " + x.Name})
+	}
+
+	g.jobs = g.jobs[:0]
+	for _, x := range g.Config.Engine.Jobs {
+		j, err := parseJob(x)
+		if err == nil {
+			x.Doer, err = g.Engine.ParseSource(engine.Source{Tag: "job." + x.Name, Text: x.Scenario, Pos: x.ScenarioPos})
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		g.jobs = append(g.jobs, j)
 	}
 
+	for _, x := range g.Config.Engine.Hooks {
+		var err error
+		if x.Doer, err = g.Engine.ParseSource(engine.Source{Tag: "hook." + x.Event, Text: x.Scenario, Pos: x.ScenarioPos}); err == nil {
+			err = g.Engine.Hook(x.Event, x.Doer)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	g.Engine.SetTraceBuffer(g.Config.Engine.TraceBuffer)
+
 	if pcfg := g.Config.Engine.Profile; pcfg.Regexp != "" {
 		if re, err := regexp.Compile(pcfg.Regexp); err != nil {
 			errs = append(errs, err)
@@ -235,6 +268,14 @@ func (g *Global) initEngine() error {
 	return helpers.FoldErrors(errs)
 }
 
+func configVars(m map[string]int) engine.Vars {
+	vars := make(engine.Vars, len(m))
+	for k, v := range m {
+		vars[k] = engine.Arg(v)
+	}
+	return vars
+}
+
 func (g *Global) initInventory(ctx context.Context) error {
 	// TODO ctx should be enough
 	if err := g.Inventory.Init(ctx, &g.Config.Engine.Inventory, g.Engine); err != nil {
@@ -244,5 +285,8 @@ func (g *Global) initInventory(ctx context.Context) error {
 	if err == nil {
 		err = g.Inventory.Persist.Load()
 	}
+	if err == nil && g.Config.Engine.Inventory.Persist {
+		err = g.Inventory.Journal.Init(filepath.Join(g.Config.Persist.Root, "inventory-journal"), g.Log)
+	}
 	return errors.Annotate(err, "initInventory")
 }
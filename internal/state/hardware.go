@@ -1,6 +1,7 @@
 package state
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -16,6 +17,7 @@ import (
 	"github.com/temoto/vender/hardware/mega-client"
 	"github.com/temoto/vender/hardware/text_display"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/internal/types"
 	"github.com/temoto/vender/log2"
 )
@@ -244,12 +246,18 @@ func (g *Global) GetDeviceConfig(name string) (DeviceConfig, error) {
 
 // Drivers call RegisterDevice to declare device support.
 // probe is called only for devices enabled in config.
+// Condition `{name}.ready` is registered for any supported device, false if not enabled.
 func (g *Global) RegisterDevice(name string, dev types.Devicer, probe func() error) error {
 	d, ok, err := g.getDevice(name)
 	g.Log.Debugf("RegisterDevice name=%s ok=%t err=%v", name, ok, err)
 	if err != nil {
 		return err
 	}
+	doReady := engine.Cond{
+		Name: name + ".ready",
+		F:    func(context.Context) (bool, error) { return g.deviceReady(name), nil },
+	}
+	g.Engine.Register(doReady.Name, doReady, engine.Meta{Description: "device is online and ready", Device: name, Safe: true})
 	if !ok {
 		// device is not listed in config
 		return nil
@@ -269,6 +277,22 @@ func (g *Global) RegisterDevice(name string, dev types.Devicer, probe func() err
 	return err
 }
 
+func (g *Global) deviceReady(name string) bool {
+	d, ok, err := g.getDevice(name)
+	if err != nil || !ok {
+		return false
+	}
+	d.RLock()
+	defer d.RUnlock()
+	if d.dev == nil {
+		return false
+	}
+	if v, ok := d.dev.(types.DeviceValidator); ok {
+		return v.ValidateOnline() == nil && v.ValidateErrorCode() == nil
+	}
+	return true
+}
+
 func (g *Global) CheckDevices() error {
 	if err := g.initDevices(); err != nil {
 		return err
@@ -0,0 +1,131 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/errors"
+	engine_config "github.com/temoto/vender/internal/engine/config"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+const defaultJobEvery = 24 * time.Hour
+
+// job is parsed engine_config.Job.
+type job struct {
+	config *engine_config.Job
+	every  time.Duration
+	at     time.Duration // since midnight, valid if hasAt
+	hasAt  bool
+	idle   bool
+}
+
+func parseJob(x *engine_config.Job) (*job, error) {
+	j := &job{config: x, every: defaultJobEvery}
+	var err error
+	if x.Every != "" {
+		if j.every, err = time.ParseDuration(x.Every); err != nil {
+			return nil, errors.Annotatef(err, "job=%s every", x.Name)
+		}
+		if j.every <= 0 {
+			return nil, errors.NotValidf("job=%s every=%s", x.Name, x.Every)
+		}
+	}
+	if x.At != "" {
+		t, err := time.Parse("15:04", x.At)
+		if err != nil {
+			return nil, errors.Annotatef(err, "job=%s at", x.Name)
+		}
+		j.at = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		j.hasAt = true
+	}
+	switch x.When {
+	case "", "idle":
+		j.idle = true
+	case "any":
+	default:
+		return nil, errors.NotValidf("job=%s when=%s expected idle|any", x.Name, x.When)
+	}
+	return j, nil
+}
+
+// next returns time of next run after now.
+// With `at` runs are aligned to that time of day: every=6h at=03:00 runs at 03,09,15,21.
+func (self *job) next(now time.Time) time.Time {
+	if !self.hasAt {
+		return now.Add(self.every)
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(self.at)
+	for t.After(now) {
+		t = t.Add(-self.every)
+	}
+	for !t.After(now) {
+		t = t.Add(self.every)
+	}
+	return t
+}
+
+// RunJobs starts periodic jobs from config, call after UI is initialized.
+func (g *Global) RunJobs(ctx context.Context) {
+	for _, j := range g.jobs {
+		go g.jobLoop(ctx, j)
+	}
+}
+
+func (g *Global) jobLoop(ctx context.Context, j *job) {
+	stopch := g.Alive.StopChan()
+	for {
+		at := j.next(time.Now())
+		g.Log.Debugf("job=%s next=%s", j.config.Name, at.Format(time.RFC3339))
+		tmr := time.NewTimer(time.Until(at))
+		select {
+		case <-tmr.C:
+			g.RunJob(ctx, j.config.Name)
+		case <-stopch:
+			tmr.Stop()
+			return
+		}
+	}
+}
+
+// RunJob executes job now, with IdleEngine priority and UI idle if `when=idle`.
+// Engine slot is taken first, so UI stays available while job is queued.
+// Outcome is reported to telemetry.
+func (g *Global) RunJob(ctx context.Context, name string) error {
+	var j *job
+	for _, x := range g.jobs {
+		if x.config.Name == name {
+			j = x
+		}
+	}
+	if j == nil {
+		return errors.NotFoundf("job=%s", name)
+	}
+
+	begin := time.Now()
+	exec := func(ctx context.Context) error {
+		begin = time.Now()
+		return g.Engine.Exec(ctx, j.config.Doer)
+	}
+	err := g.ScheduleSync(ctx, tele_api.Priority_IdleEngine, func(ctx context.Context) error {
+		if j.idle {
+			return g.ScheduleSync(ctx, tele_api.Priority_IdleUser, exec)
+		}
+		return exec(ctx)
+	})
+	err = errors.Annotatef(err, "job=%s", name)
+
+	tj := &tele_api.Telemetry_Job{
+		Name:     name,
+		Begin:    begin.UnixNano(),
+		Duration: int64(time.Since(begin)),
+	}
+	if err != nil {
+		tj.Error = err.Error()
+		g.Log.Error(err)
+	} else {
+		g.Log.Infof("job=%s done duration=%v", name, time.Duration(tj.Duration))
+	}
+	g.Tele.Job(tj)
+	return err
+}
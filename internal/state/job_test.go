@@ -0,0 +1,97 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/temoto/alive/v2"
+	"github.com/temoto/vender/internal/engine"
+	engine_config "github.com/temoto/vender/internal/engine/config"
+	"github.com/temoto/vender/log2"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+func TestJobNext(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 5, 17, 10, 30, 0, 0, time.Local)
+	cases := []struct {
+		every  string
+		at     string
+		expect time.Time
+	}{
+		{"", "", now.Add(24 * time.Hour)},
+		{"15m", "", now.Add(15 * time.Minute)},
+		{"", "03:00", time.Date(2020, 5, 18, 3, 0, 0, 0, time.Local)},
+		{"", "11:00", time.Date(2020, 5, 17, 11, 0, 0, 0, time.Local)},
+		{"6h", "03:00", time.Date(2020, 5, 17, 15, 0, 0, 0, time.Local)},
+		{"6h", "22:00", time.Date(2020, 5, 17, 16, 0, 0, 0, time.Local)},
+		{"1h", "10:30", time.Date(2020, 5, 17, 11, 30, 0, 0, time.Local)},
+	}
+	for _, c := range cases {
+		j, err := parseJob(&engine_config.Job{Name: "test", Every: c.every, At: c.at})
+		require.NoError(t, err)
+		assert.Equal(t, c.expect, j.next(now), "every=%s at=%s", c.every, c.at)
+	}
+
+	for _, x := range []engine_config.Job{
+		{Name: "bad-every", Every: "often"},
+		{Name: "zero-every", Every: "0s"},
+		{Name: "bad-at", At: "25:00"},
+		{Name: "bad-when", When: "never"},
+	} {
+		_, err := parseJob(&x)
+		assert.Error(t, err, x.Name)
+	}
+}
+
+type jobTeler struct {
+	tele_api.Teler
+	jobs []*tele_api.Telemetry_Job
+}
+
+func (self *jobTeler) Job(j *tele_api.Telemetry_Job) { self.jobs = append(self.jobs, j) }
+
+func TestRunJob(t *testing.T) {
+	t.Parallel()
+
+	log := log2.NewTest(t, log2.LDebug)
+	teler := &jobTeler{Teler: tele_api.NewStub()}
+	g := &Global{
+		Alive:  alive.NewAlive(),
+		Engine: engine.NewEngine(log),
+		Log:    log,
+		Runner: engine.NewRunner(),
+		Tele:   teler,
+	}
+	defer g.Alive.Stop()
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, log2.ContextKey, log)
+	ctx = context.WithValue(ctx, engine.ContextKey, g.Engine)
+	ctx = context.WithValue(ctx, ContextKey, g)
+	go g.Runner.Loop(ctx, g.Alive)
+
+	count := 0
+	g.Engine.Register("rinse", engine.Func0{F: func() error { count++; return nil }})
+	g.Config = &Config{}
+	g.Config.Engine.Jobs = []*engine_config.Job{
+		{Name: "rinse", Every: "1h", Scenario: "rinse rinse", When: "any"},
+		{Name: "broken", Scenario: "rinse missing", When: "any"},
+	}
+	require.NoError(t, g.initEngine())
+
+	require.NoError(t, g.RunJob(ctx, "rinse"))
+	assert.Equal(t, 2, count)
+	require.Error(t, g.RunJob(ctx, "broken"))
+	require.Error(t, g.RunJob(ctx, "unknown"))
+
+	require.Len(t, teler.jobs, 2)
+	assert.Equal(t, "rinse", teler.jobs[0].Name)
+	assert.Equal(t, "", teler.jobs[0].Error)
+	assert.Equal(t, "broken", teler.jobs[1].Name)
+	assert.Contains(t, teler.jobs[1].Error, "missing")
+	assert.Equal(t, uint64(2), g.Runner.Stats().Done)
+}
@@ -26,12 +26,14 @@ func NewContext(log *log2.Log, teler tele_api.Teler) (context.Context, *state.Gl
 		Engine:    engine.NewEngine(log),
 		Inventory: new(inventory.Inventory),
 		Log:       log,
+		Runner:    engine.NewRunner(),
 		Tele:      teler,
 	}
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, log2.ContextKey, log)
 	ctx = context.WithValue(ctx, engine.ContextKey, g.Engine)
 	ctx = context.WithValue(ctx, state.ContextKey, g)
+	go g.Runner.Loop(ctx, g.Alive)
 
 	return ctx, g
 }
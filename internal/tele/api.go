@@ -62,6 +62,7 @@ func (self *tele) Report(ctx context.Context, serviceTag bool) error {
 		AtService:    serviceTag,
 		BuildVersion: g.BuildVersion,
 	}
+	tm.Inventory.Journal = g.Inventory.Journal.TakeTele()
 	err := self.qpushTelemetry(tm)
 	if err != nil {
 		self.log.Errorf("CRITICAL qpushTelemetry tm=%#v err=%v", tm, err)
@@ -108,3 +109,36 @@ func (self *tele) Transaction(tx *tele_api.Telemetry_Transaction) {
 		self.log.Errorf("CRITICAL transaction=%#v err=%v", tx, err)
 	}
 }
+
+func (self *tele) Job(job *tele_api.Telemetry_Job) {
+	if !self.config.Enabled {
+		self.log.Infof(logMsgDisabled)
+		return
+	}
+	err := self.qpushTelemetry(&tele_api.Telemetry{Job: job})
+	if err != nil {
+		self.log.Errorf("CRITICAL job=%#v err=%v", job, err)
+	}
+}
+
+func (self *tele) Menu(menu *tele_api.Telemetry_Menu) {
+	if !self.config.Enabled {
+		self.log.Infof(logMsgDisabled)
+		return
+	}
+	err := self.qpushTelemetry(&tele_api.Telemetry{Menu: menu})
+	if err != nil {
+		self.log.Errorf("CRITICAL menu=%#v err=%v", menu, err)
+	}
+}
+
+func (self *tele) Refund(refund *tele_api.Telemetry_Refund) {
+	if !self.config.Enabled {
+		self.log.Infof(logMsgDisabled)
+		return
+	}
+	err := self.qpushTelemetry(&tele_api.Telemetry{Refund: refund})
+	if err != nil {
+		self.log.Errorf("CRITICAL refund=%#v err=%v", refund, err)
+	}
+}
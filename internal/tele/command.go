@@ -10,6 +10,8 @@ import (
 	"github.com/juju/errors"
 	"github.com/skip2/go-qrcode"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/engine/inventory"
 	"github.com/temoto/vender/internal/state"
 	tele_api "github.com/temoto/vender/tele"
 )
@@ -32,6 +34,11 @@ func (self *tele) onCommandMessage(ctx context.Context, payload []byte) bool {
 	if cmd.Deadline != 0 && now > cmd.Deadline {
 		self.CommandReplyErr(cmd, fmt.Errorf("deadline"))
 	} else {
+		// scheduler orders by deadline and drops task if it passed in queue,
+		// started command is not canceled by deadline
+		if cmd.Deadline != 0 {
+			ctx = engine.WithQueueDeadline(ctx, time.Unix(0, cmd.Deadline))
+		}
 		// TODO store command in persistent queue, acknowledge now, execute later
 		err = self.dispatchCommand(ctx, cmd)
 		self.CommandReplyErr(cmd, err)
@@ -60,6 +67,9 @@ func (self *tele) dispatchCommand(ctx context.Context, cmd *tele_api.Command) er
 	case *tele_api.Command_Show_QR:
 		return self.cmdShowQR(ctx, cmd, task.Show_QR)
 
+	case *tele_api.Command_Abort:
+		return self.cmdAbort(ctx, cmd)
+
 	default:
 		err := fmt.Errorf("unknown command=%#v", cmd)
 		self.log.Error(err)
@@ -73,9 +83,13 @@ func (self *tele) cmdReport(ctx context.Context, cmd *tele_api.Command) error {
 
 func (self *tele) cmdLock(ctx context.Context, cmd *tele_api.Command, arg *tele_api.Command_ArgLock) error {
 	g := state.GetGlobal(ctx)
-	return g.ScheduleSync(ctx, cmd.Priority, func(context.Context) error {
-		time.Sleep(time.Duration(arg.Duration) * time.Second)
-		return nil
+	return g.ScheduleSync(ctx, cmd.Priority, func(ctx context.Context) error {
+		select {
+		case <-time.After(time.Duration(arg.Duration) * time.Second):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	})
 }
 
@@ -92,6 +106,7 @@ func (self *tele) cmdExec(ctx context.Context, cmd *tele_api.Command, arg *tele_
 		return err
 	}
 
+	ctx = inventory.WithSource(ctx, inventory.SourceTele)
 	err = g.ScheduleSync(ctx, cmd.Priority, doer.Do)
 	err = errors.Annotate(err, "schedule")
 	return err
@@ -118,7 +133,11 @@ func (self *tele) cmdStop(ctx context.Context, cmd *tele_api.Command, arg *tele_
 	go func() {
 		delay := helpers.IntSecondDefault(g.Config.Tele.FIXME_stopDelaySec, 7*time.Second)
 		g.Log.Debugf("cmdStop arg=%s crutch delay=%v", proto.MarshalTextString(arg), delay)
-		time.Sleep(delay)
+		select {
+		case <-time.After(delay):
+		case <-g.Alive.StopChan(): // already stopping
+			return
+		}
 
 		g.ScheduleSync(ctx, cmd.Priority, func(context.Context) error {
 			g.Stop()
@@ -138,6 +157,16 @@ func (self *tele) cmdStop(ctx context.Context, cmd *tele_api.Command, arg *tele_
 	return nil
 }
 
+// Cancel current vend, UI runs on_menu_error.
+// Not scheduled, UI is busy with the vend.
+func (self *tele) cmdAbort(ctx context.Context, cmd *tele_api.Command) error {
+	g := state.GetGlobal(ctx)
+	if !g.Engine.Abort() {
+		return errors.Errorf("nothing to abort")
+	}
+	return nil
+}
+
 func (self *tele) cmdShowQR(ctx context.Context, cmd *tele_api.Command, arg *tele_api.Command_ArgShowQR) error {
 	if arg == nil {
 		return errInvalidArg
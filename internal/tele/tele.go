@@ -8,6 +8,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/temoto/spq"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/log2"
 	tele_api "github.com/temoto/vender/tele"
 	tele_config "github.com/temoto/vender/tele/config"
@@ -35,6 +36,7 @@ type tele struct { //nolint:maligned
 	vmId          int32
 	stateInterval time.Duration
 	stat          tele_api.Stat
+	engine        *engine.Engine // latency source, nil if ctx has no engine
 }
 
 func New() tele_api.Teler {
@@ -56,6 +58,7 @@ func (self *tele) Init(ctx context.Context, log *log2.Log, teleConfig tele_confi
 	self.vmId = int32(self.config.VmId)
 	self.stateInterval = helpers.IntSecondDefault(self.config.StateIntervalSec, defaultStateInterval)
 	self.stat.Locked_Reset()
+	self.engine, _ = ctx.Value(engine.ContextKey).(*engine.Engine)
 
 	willPayload := []byte{byte(tele_api.State_Disconnected)}
 	// test code sets .transport
@@ -212,6 +215,9 @@ func (self *tele) qpushTelemetry(tm *tele_api.Telemetry) error {
 	}
 	self.stat.Lock()
 	defer self.stat.Unlock()
+	if self.engine != nil {
+		self.stat.Latency = latencyProto(self.engine.TakeLatency())
+	}
 	tm.Stat = &self.stat.Telemetry_Stat
 	err := self.qpushTagProto(qTelemetry, tm)
 	self.stat.Locked_Reset()
@@ -251,3 +257,15 @@ func (self *tele) qsendTelemetry(tm *tele_api.Telemetry) bool {
 	// self.log.Debugf("SendTelemetry %x", payload)
 	return self.transport.SendTelemetry(payload)
 }
+
+func latencyProto(m map[string]engine.Latency) map[string]*tele_api.Telemetry_Latency {
+	if len(m) == 0 {
+		return nil
+	}
+	us := func(d time.Duration) uint32 { return uint32(d / time.Microsecond) }
+	result := make(map[string]*tele_api.Telemetry_Latency, len(m))
+	for name, l := range m {
+		result[name] = &tele_api.Telemetry_Latency{Count: uint32(l.Count), P50: us(l.P50), P95: us(l.P95), Max: us(l.Max)}
+	}
+	return result
+}
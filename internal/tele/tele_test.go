@@ -79,6 +79,11 @@ func TestCommand(t *testing.T) {
 				assert.Nil(t, tm.Error)
 				assert.Equal(t, env.vmid, tm.VmId)
 				require.NotNil(t, tm.Inventory)
+				require.Len(t, tm.Inventory.Journal, 2)
+				assert.Equal(t, "rock", tm.Inventory.Journal[1].Name)
+				assert.Equal(t, "set", tm.Inventory.Journal[1].Reason)
+				assert.Equal(t, float32(42), tm.Inventory.Journal[1].After)
+				tm.Inventory.Journal = nil
 				assert.Equal(t, `stocks:<value:3 name:"paper" valuef:3.14 > stocks:<value:42 name:"rock" valuef:42 > `, proto.CompactTextString(tm.Inventory))
 				// TODO
 				t.Logf("cashbox=%#v", tm.MoneyCashbox)
@@ -207,6 +212,29 @@ func TestApi(t *testing.T) {
 				assert.Equal(t, e.Error(), tm.Error.Message)
 				assert.Equal(t, env.version, tm.BuildVersion)
 			}},
+		{name: "latency",
+			config: `engine { profile { regexp = "^slow" } }`,
+			check: func(t testing.TB, env *tenv) {
+				g := state.GetGlobal(env.ctx)
+				g.Engine.Register("slow", engine.Func0{Name: "slow", F: func() error {
+					time.Sleep(time.Millisecond)
+					return nil
+				}})
+				for i := 0; i < 3; i++ {
+					require.NoError(t, g.Engine.Exec(env.ctx, g.Engine.Resolve("slow")))
+				}
+				env.tele.Error(fmt.Errorf("with stat"))
+				b := <-env.trans.outTelemetry
+				var tm tele_api.Telemetry
+				require.NoError(t, proto.Unmarshal(b, &tm))
+				require.NotNil(t, tm.Stat)
+				l := tm.Stat.Latency["slow"]
+				require.NotNil(t, l)
+				assert.Equal(t, uint32(3), l.Count)
+				assert.GreaterOrEqual(t, l.P50, uint32(1000))
+				assert.GreaterOrEqual(t, l.Max, l.P95)
+				assert.GreaterOrEqual(t, l.P95, l.P50)
+			}},
 		{name: "state",
 			config: ``,
 			check: func(t testing.TB, env *tenv) {
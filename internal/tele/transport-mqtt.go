@@ -14,6 +14,7 @@ import (
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/juju/errors"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/log2"
 	tele_config "github.com/temoto/vender/tele/config"
 )
@@ -87,6 +88,7 @@ func (self *transportMqtt) Init(ctx context.Context, log *log2.Log, teleConfig t
 		SetCredentialsProvider(credFun).
 		SetDefaultPublishHandler(defaultHandler).
 		SetKeepAlive(keepaliveTimeout).
+		SetOnConnectHandler(func(mqtt.Client) { engine.Emit(ctx, engine.Event{Name: engine.EventTeleConnected}) }).
 		SetMaxReconnectInterval(connectTimeout).
 		SetMessageChannelDepth(1).
 		SetOrderMatters(false).
@@ -17,6 +17,7 @@ const (
 	EventLock
 	EventService
 	EventStop
+	EventAvailability // menu items availability changed
 )
 
 type Event struct {
@@ -11,3 +11,9 @@ func (oe DeviceOfflineError) Error() string { return fmt.Sprintf("%s is offline"
 type Devicer interface {
 	Name() string
 }
+
+// DeviceValidator is optionally implemented by devices to support `{name}.ready` engine condition.
+type DeviceValidator interface {
+	ValidateOnline() error
+	ValidateErrorCode() error
+}
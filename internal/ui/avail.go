@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/engine/inventory"
+	tele_api "github.com/temoto/vender/tele"
+)
+
+// availability of menu items is recomputed on stock and device changes,
+// UI is notified only when set of available items changes.
+type availability struct {
+	sync.Mutex
+	update      sync.Mutex // serializes availUpdate, Validate mutates menu doers
+	valid       bool
+	available   []string // sorted codes
+	unavailable []string
+	maxPrice    currency.Amount
+	dirty       chan struct{} // request recompute
+	changed     chan struct{} // UI refresh
+}
+
+func (self *UI) availInit(ctx context.Context) {
+	self.avail.dirty = make(chan struct{}, 1)
+	self.avail.changed = make(chan struct{}, 1)
+	self.g.Inventory.Subscribe(func(*inventory.Stock) { self.availRequest() })
+	onDevice := engine.Func{Name: "ui.availability", F: func(context.Context) error {
+		self.availRequest()
+		return nil
+	}}
+	for _, ev := range []string{engine.EventDeviceOffline, engine.EventDeviceRecovered} {
+		if err := self.g.Engine.Hook(ev, onDevice); err != nil {
+			self.g.Log.Fatal(err)
+		}
+	}
+	go self.availLoop(ctx)
+}
+
+func (self *UI) availRequest() {
+	select {
+	case self.avail.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (self *UI) availLoop(ctx context.Context) {
+	stopch := self.g.Alive.StopChan()
+	for {
+		select {
+		case <-self.avail.dirty:
+			self.availUpdate(ctx)
+		case <-stopch:
+			return
+		}
+	}
+}
+
+// availUpdate validates every menu item, returns max price of available items.
+// Called from availLoop and front begin.
+func (self *UI) availUpdate(ctx context.Context) currency.Amount {
+	self.avail.update.Lock()
+	defer self.avail.update.Unlock()
+
+	available := make([]string, 0, len(self.menu))
+	unavailable := make([]string, 0)
+	max := currency.Amount(0)
+	for code, item := range self.menu {
+		if err := item.D.Validate(); err != nil {
+			self.g.Log.Debug(errors.Annotate(err, item.String()))
+			unavailable = append(unavailable, code)
+			continue
+		}
+		available = append(available, code)
+		if item.Price > max {
+			max = item.Price
+		}
+	}
+	sort.Strings(available)
+	sort.Strings(unavailable)
+
+	self.avail.Lock()
+	same := self.avail.valid && strings.Join(self.avail.available, ",") == strings.Join(available, ",")
+	self.avail.valid = true
+	self.avail.available, self.avail.unavailable, self.avail.maxPrice = available, unavailable, max
+	self.avail.Unlock()
+	if same {
+		return max
+	}
+
+	self.g.Log.Infof("ui menu available=%v unavailable=%v", available, unavailable)
+	self.g.Tele.Menu(&tele_api.Telemetry_Menu{Available: available, Unavailable: unavailable})
+	select {
+	case self.avail.changed <- struct{}{}:
+	default:
+	}
+	return max
+}
+
+// Unavailable returns menu codes failing validation, as of last stock or device change.
+func (self *UI) Unavailable() []string {
+	self.avail.Lock()
+	defer self.avail.Unlock()
+	return append([]string(nil), self.avail.unavailable...)
+}
+
+func (self *UI) availMax() currency.Amount {
+	self.avail.Lock()
+	defer self.avail.Unlock()
+	return self.avail.maxPrice
+}
+
+func (self *UI) anyAvailable() bool {
+	self.avail.Lock()
+	defer self.avail.Unlock()
+	return len(self.avail.available) != 0
+}
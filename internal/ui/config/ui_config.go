@@ -14,6 +14,7 @@ type Config struct { //nolint:maligned
 		MsgMenuCodeInvalid        string `hcl:"msg_menu_code_invalid"`
 		MsgMenuInsufficientCredit string `hcl:"msg_menu_insufficient_credit"`
 		MsgMenuNotAvailable       string `hcl:"msg_menu_not_available"`
+		MsgMenuUnavailable        string `hcl:"msg_menu_unavailable"` // %s=codes, empty hides
 
 		MsgCream   string `hcl:"msg_cream"`
 		MsgSugar   string `hcl:"msg_sugar"`
@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -13,8 +14,8 @@ import (
 	"github.com/temoto/vender/hardware/text_display"
 	"github.com/temoto/vender/helpers"
 	"github.com/temoto/vender/internal/engine"
+	"github.com/temoto/vender/internal/engine/inventory"
 	"github.com/temoto/vender/internal/money"
-	"github.com/temoto/vender/internal/state"
 	"github.com/temoto/vender/internal/types"
 	tele_api "github.com/temoto/vender/tele"
 )
@@ -60,37 +61,48 @@ func (self *UI) onFrontBegin(ctx context.Context) State {
 		}
 	}
 
-	var err error
-	self.FrontMaxPrice, err = menuMaxPrice(ctx, self.menu)
-	if err != nil {
-		self.g.Error(err)
-		return StateBroken
+	self.FrontMaxPrice = self.availUpdate(ctx)
+	select { // fresh state, drop pending notification
+	case <-self.avail.changed:
+	default:
+	}
+	if !self.anyAvailable() {
+		return self.frontNothingAvailable(ctx)
 	}
 	self.g.Tele.State(tele_api.State_Nominal)
 	return StateFrontSelect
 }
 
-func menuMaxPrice(ctx context.Context, m Menu) (currency.Amount, error) {
-	g := state.GetGlobal(ctx)
-	max := currency.Amount(0)
-	empty := true
-	for _, item := range m {
-		valErr := item.D.Validate()
-		if valErr == nil {
-			empty = false
-			if item.Price > max {
-				max = item.Price
-			}
-		} else {
-			// TODO report menu errors once or less often than every ui cycle
-			valErr = errors.Annotate(valErr, item.String())
-			g.Log.Debug(valErr)
+// frontNothingAvailable keeps money off until stock or device change makes some item available.
+func (self *UI) frontNothingAvailable(ctx context.Context) State {
+	self.g.Log.Errorf("ui-front menu len=%d no valid items", len(self.menu))
+	self.g.Tele.State(tele_api.State_Problem)
+	moneysys := money.GetGlobal(ctx)
+	if err := moneysys.SetAcceptMax(ctx, 0); err != nil {
+		self.g.Error(err)
+	}
+	self.display.SetLines(self.g.Config.UI.Front.MsgMenuNotAvailable, self.unavailableLine())
+	for self.g.Alive.IsRunning() {
+		e := self.wait(time.Minute)
+		switch e.Kind {
+		case types.EventAvailability:
+			return StateFrontBegin
+		case types.EventService:
+			return StateServiceBegin
+		case types.EventLock, types.EventStop:
+			return StateFrontEnd
 		}
 	}
-	if empty {
-		return 0, errors.Errorf("menu len=%d no valid items", len(m))
+	return StateFrontEnd
+}
+
+// unavailableLine formats unavailable menu codes, empty if all available or message is not configured.
+func (self *UI) unavailableLine() string {
+	codes := self.Unavailable()
+	if len(codes) == 0 || self.g.Config.UI.Front.MsgMenuUnavailable == "" {
+		return ""
 	}
-	return max, nil
+	return fmt.Sprintf(self.g.Config.UI.Front.MsgMenuUnavailable, strings.Join(codes, ","))
 }
 
 func (self *UI) onFrontSelect(ctx context.Context) State {
@@ -180,6 +192,19 @@ func (self *UI) onFrontSelect(ctx context.Context) State {
 			self.g.Log.Debugf("ui-front money event=%s", e.String())
 			go moneysys.AcceptCredit(ctx, self.FrontMaxPrice, alive.StopChan(), self.eventch)
 
+		case types.EventAvailability:
+			self.FrontMaxPrice = self.availMax() // applied by next AcceptCredit
+			if !self.anyAvailable() {
+				if moneysys.Credit(ctx) == 0 {
+					return StateFrontBegin
+				}
+				// customer may still abort and get money back
+				if err := moneysys.SetAcceptMax(ctx, 0); err != nil {
+					self.g.Error(err)
+				}
+			}
+			goto refresh
+
 		case types.EventService:
 			return StateServiceBegin
 
@@ -205,6 +230,8 @@ func (self *UI) frontSelectShow(ctx context.Context, credit currency.Amount) {
 	if (credit != 0) || (len(self.inputBuf) > 0) {
 		l1 = self.g.Config.UI.Front.MsgCredit + credit.FormatCtx(ctx)
 		l2 = fmt.Sprintf(self.g.Config.UI.Front.MsgInputCode, string(self.inputBuf))
+	} else {
+		l2 = self.unavailableLine()
 	}
 	self.display.SetLines(l1, l2)
 }
@@ -285,10 +312,21 @@ func (self *UI) onFrontAccept(ctx context.Context) State {
 	}
 
 	self.g.Log.Debugf("ui-front selected=%s begin", selected.String())
-	if err := moneysys.WithdrawPrepare(ctx, selected.Price); err != nil {
-		self.g.Log.Errorf("ui-front CRITICAL error while return change")
+	if err := moneysys.WithdrawPrepare(ctx, selected.Price, selected.Code); err != nil {
+		// cashless reader denied, customer may choose another item or pay otherwise
+		self.g.Log.Errorf("ui-front selected=%s withdraw err=%v", selected.String(), err)
+		self.display.SetLines(uiConfig.Front.MsgError, uiConfig.Front.MsgMenuInsufficientCredit)
+		return StateFrontSelect
+	}
+	if moneysys.GetCashlessVend() != 0 {
+		teletx.PaymentMethod = tele_api.PaymentMethod_Cashless
 	}
 	itemCtx := money.SetCurrentPrice(ctx, selected.Price)
+	itemCtx = inventory.WithSource(itemCtx, inventory.SourceSale+" menu."+selected.Code)
+	itemCtx = engine.WithVars(itemCtx, engine.Vars{
+		"cream": engine.Arg(self.FrontResult.Cream),
+		"sugar": engine.Arg(self.FrontResult.Sugar),
+	})
 	if tuneCream := ScaleTuneRate(self.FrontResult.Cream, MaxCream, DefaultCream); tuneCream != 1 {
 		const name = "cream"
 		var err error
@@ -307,19 +345,53 @@ func (self *UI) onFrontAccept(ctx context.Context) State {
 	}
 	self.display.SetLines(self.g.Config.UI.Front.MsgMaking1, self.g.Config.UI.Front.MsgMaking2)
 
-	err := self.g.Engine.Exec(itemCtx, selected.D)
+	itemCtx, release := self.g.Engine.WithAbort(itemCtx)
+	itemCtx, saga := engine.WithSaga(itemCtx)
+	trace, err := self.g.Engine.ExecTrace(itemCtx, selected.D)
+	release()
+	if vendErr := moneysys.VendResult(ctx, err == nil); vendErr != nil {
+		self.g.Error(vendErr)
+	}
 	if invErr := self.g.Inventory.Persist.Store(); invErr != nil {
 		self.g.Error(errors.Annotate(invErr, "critical inventory persist"))
 	}
 	self.g.Log.Debugf("ui-front selected=%s end err=%v", selected.String(), err)
 	if err == nil { // success path
 		self.g.Tele.Transaction(teletx)
+		self.g.Engine.Emit(ctx, engine.Event{
+			Name:    engine.EventTransactionComplete,
+			Subject: selected.Code,
+			Vars: engine.Vars{
+				"price": engine.Arg(selected.Price),
+				"cream": engine.Arg(self.FrontResult.Cream),
+				"sugar": engine.Arg(self.FrontResult.Sugar),
+			},
+		})
 		return StateFrontEnd
 	}
 
 	self.display.SetLines(uiConfig.Front.MsgError, uiConfig.Front.MsgMenuError)
+	if errors.Cause(err) == context.Canceled {
+		err = errors.Annotate(err, "aborted")
+	}
 	err = errors.Annotatef(err, "execute %s", selected.String())
 	self.g.Error(err)
+	if trace != nil {
+		self.g.Log.Errorf("ui-front selected=%s trace:\n%s", selected.String(), trace.String())
+	}
+	teletx.Error = err.Error()
+	teletx.Trace = traceProto(trace)
+	self.g.Tele.Transaction(teletx)
+
+	// compensate completed steps before generic on_menu_error cleanup
+	// itemCtx is cancelled by release, keep values only
+	if n := saga.Len(); n != 0 {
+		if err := saga.Rollback(engine.Detach(itemCtx)); err != nil {
+			self.g.Error(errors.Annotate(err, "rollback"))
+		} else {
+			self.g.Log.Infof("rollback steps=%d success", n)
+		}
+	}
 
 	if errs := self.g.Engine.ExecList(ctx, "on_menu_error", self.g.Config.Engine.OnMenuError); len(errs) != 0 {
 		self.g.Error(errors.Annotate(helpers.FoldErrors(errs), "on_menu_error"))
@@ -382,3 +454,21 @@ func ScaleTuneRate(value, max, center uint8) float32 {
 	}
 	panic("code error")
 }
+
+func traceProto(s *engine.Span) *tele_api.Telemetry_Trace {
+	if s == nil {
+		return nil
+	}
+	t := &tele_api.Telemetry_Trace{
+		Name:     s.Name,
+		Args:     s.Args,
+		Begin:    s.Begin.UnixNano(),
+		Duration: int64(s.Duration),
+		Error:    s.Error,
+		Children: make([]*tele_api.Telemetry_Trace, len(s.Children)),
+	}
+	for i, child := range s.Children {
+		t.Children[i] = traceProto(child)
+	}
+	return t
+}
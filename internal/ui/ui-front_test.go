@@ -3,6 +3,7 @@ package ui_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -163,6 +164,90 @@ ui {
 	}
 }
 
+func TestFrontAvailability(t *testing.T) {
+	t.Parallel()
+
+	ctx, g := state_new.NewTestContext(t, "", `
+engine {
+	inventory {
+		stock "cup" { check=true register_add="ignore(?)" }
+	}
+	menu {
+		item "1" { scenario = "add.cup(1)" }
+		item "2" { scenario = "add.cup(5)" }
+	}
+}
+ui {
+	front {
+		msg_intro = "avail"
+		msg_menu_not_available = "empty"
+		msg_menu_unavailable = "n/a:%s"
+		reset_sec = 5
+	}
+}`)
+	moneysys := new(money.MoneySystem)
+	require.NoError(t, moneysys.Start(ctx))
+	env := &tenv{ctx: ctx, g: g}
+	uiTestSetup(t, env, ui.StateFrontBegin, ui.StateFrontAccept)
+	cupStock := g.Inventory.MustGet(t, "cup")
+	cupStock.Set(3)
+	go env.ui.Loop(ctx)
+
+	steps := []step{
+		{expect: env._T("avail", "n/a:2")},
+		{fun: func() { cupStock.Set(0) }},
+		{expect: env._T("empty", "n/a:1,2")},
+		{fun: func() { cupStock.Set(10) }},
+		{expect: env._T("avail", ""), inev: types.Event{Kind: types.EventStop}},
+		{},
+	}
+	uiTestWait(t, env, steps)
+	assert.Empty(t, env.ui.Unavailable())
+}
+
+// Stock changes recompute availability in background while front begin does the same.
+// Run with -race.
+func TestFrontAvailabilityConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx, g := state_new.NewTestContext(t, "", `
+engine {
+	inventory {
+		stock "cup" { check=true register_add="ignore(?)" }
+	}
+	alias "cup" { scenario = "add.cup(1)" }
+	menu {
+		item "1" { scenario = "if(stock.cup.has(2)) cup cup else cup add.cup(5) end" }
+		item "2" { scenario = "cup add.cup(5)" }
+	}
+}
+ui { front { reset_sec = 5 } }`)
+	moneysys := new(money.MoneySystem)
+	require.NoError(t, moneysys.Start(ctx))
+	env := &tenv{ctx: ctx, g: g}
+	uiTestSetup(t, env, ui.StateFrontBegin, ui.StateStop)
+	cupStock := g.Inventory.MustGet(t, "cup")
+	cupStock.Set(3)
+	stopch := g.Alive.StopChan()
+	go func() {
+		for {
+			select {
+			case <-env.displayUpdated:
+			case <-stopch:
+				return
+			}
+		}
+	}()
+	go env.ui.Loop(ctx)
+
+	for i := 0; i < 100; i++ {
+		cupStock.Set(float32(i%2) * 6)
+		time.Sleep(time.Millisecond)
+	}
+	g.Alive.Stop()
+	g.Alive.Wait()
+}
+
 // This test ensures particular behavior of currently operated coffee machine tuning.
 func TestScaleTuneRate(t *testing.T) {
 	t.Run("cream", func(t *testing.T) {
@@ -222,10 +222,20 @@ func (self *UI) onServiceInventory() State {
 		return StateServiceMenu
 	}
 	invCurrent := self.Service.invList[self.Service.invIdx]
-	self.display.SetLines(
-		fmt.Sprintf("I%d %s", invCurrent.Code, invCurrent.Name),
-		fmt.Sprintf("%.1f %s\x00", invCurrent.Value(), string(self.inputBuf)), // TODO configurable decimal point
-	)
+	line1 := fmt.Sprintf("I%d %s", invCurrent.Code, invCurrent.Name)
+	if expire := invCurrent.Expire(); !expire.IsZero() {
+		// earliest lot expiry day, ! when expired
+		mark := " "
+		if invCurrent.Expired(time.Now()) {
+			mark = "!"
+		}
+		line1 += mark + expire.Format("02.01")
+	}
+	line2 := fmt.Sprintf("%.1f %s\x00", invCurrent.Value(), string(self.inputBuf)) // TODO configurable decimal point
+	if fill, ok := invCurrent.Fill(); ok {
+		line2 = fmt.Sprintf("%.1f %.0f%% %s\x00", invCurrent.Value(), fill, string(self.inputBuf))
+	}
+	self.display.SetLines(line1, line2)
 
 	next, e := self.serviceWaitInput()
 	if next != StateDefault {
@@ -244,6 +254,27 @@ func (self *UI) onServiceInventory() State {
 	case e.Key == input.EvendKeyDot || e.IsDigit():
 		self.inputBuf = append(self.inputBuf, byte(e.Key))
 
+	case e.Key == input.EvendKeySugarMore:
+		// refill +N with number input, to capacity without
+		var err error
+		if len(self.inputBuf) == 0 {
+			_, err = invCurrent.RefillFull(inventory.SourceService)
+		} else {
+			var x float64
+			if x, err = strconv.ParseFloat(string(self.inputBuf), 32); err == nil {
+				err = invCurrent.Refill(inventory.SourceService, float32(x))
+			}
+		}
+		if err != nil {
+			self.g.Log.Errorf("ui onServiceInventory refill inputBuf='%s' err=%v", string(self.inputBuf), err)
+			self.inputBuf = self.inputBuf[:0]
+			self.display.SetLines(MsgError, "refill-invalid") // FIXME extract message string
+			self.serviceWaitInput()
+			return StateServiceInventory
+		}
+		self.inputBuf = self.inputBuf[:0]
+		self.Service.askReport = true
+
 	case input.IsAccept(&e):
 		if len(self.inputBuf) == 0 {
 			self.g.Log.Errorf("ui onServiceInventory input=accept inputBuf=empty")
@@ -262,7 +293,7 @@ func (self *UI) onServiceInventory() State {
 		}
 
 		invCurrent := self.Service.invList[self.Service.invIdx]
-		invCurrent.Set(float32(x))
+		invCurrent.SetBy(inventory.SourceService, float32(x))
 		self.Service.askReport = true
 
 	case input.IsReject(&e):
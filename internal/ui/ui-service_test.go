@@ -2,10 +2,13 @@ package ui_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/temoto/vender/currency"
+	"github.com/temoto/vender/hardware/input"
+	"github.com/temoto/vender/internal/engine/inventory"
 	"github.com/temoto/vender/internal/money"
 	state_new "github.com/temoto/vender/internal/state/new"
 	"github.com/temoto/vender/internal/ui"
@@ -93,6 +96,41 @@ engine { inventory {
 	uiTestWait(t, env, steps)
 }
 
+func TestServiceInventoryRefill(t *testing.T) {
+	t.Parallel()
+
+	ctx, g := state_new.NewTestContext(t, "", `
+engine { inventory {
+	stock "cup" { code=3 capacity=200 }
+}}`)
+	env := &tenv{ctx: ctx, g: g}
+	g.Config.UI.Service.Auth.Enable = false
+	uiTestSetup(t, env, ui.StateServiceBegin, ui.StateServiceEnd)
+	go env.ui.Loop(ctx)
+
+	refill := env._Key(input.EvendKeySugarMore)
+	steps := []step{
+		{expect: env._T("Menu", "1 inventory"), inev: env._KeyAccept},
+		{expect: env._T("I3 cup", "0.0 0% \x00"), inev: env._Key('5')},
+		{expect: env._T("I3 cup", "0.0 0% 5\x00"), inev: env._Key('0')},
+		{expect: env._T("I3 cup", "0.0 0% 50\x00"), inev: refill},
+		{expect: env._T("I3 cup", "50.0 25% \x00"), inev: refill},
+		{expect: env._T("I3 cup", "200.0 100% \x00"), inev: env._KeyReject},
+		{expect: env._T("Menu", "1 inventory"), inev: env._KeyReject},
+		{},
+	}
+	uiTestWait(t, env, steps)
+
+	changes, err := g.Inventory.Journal.Query("cup", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	for i, expect := range []float32{50, 150} {
+		assert.Equal(t, inventory.ReasonRefill, changes[i].Reason)
+		assert.Equal(t, inventory.SourceService, changes[i].Source)
+		assert.Equal(t, expect, changes[i].After-changes[i].Before)
+	}
+}
+
 func TestServiceTest(t *testing.T) {
 	t.Parallel()
 
@@ -9,6 +9,7 @@ import (
 	"github.com/temoto/vender/hardware/input"
 	"github.com/temoto/vender/hardware/text_display"
 	"github.com/temoto/vender/helpers"
+	"github.com/temoto/vender/internal/engine"
 	"github.com/temoto/vender/internal/state"
 	"github.com/temoto/vender/internal/types"
 	ui_config "github.com/temoto/vender/internal/ui/config"
@@ -31,6 +32,8 @@ type UI struct { //nolint:maligned
 	eventch      chan types.Event
 	inputch      chan types.InputEvent
 	lock         uiLock
+	lockq        *engine.Run // orders ScheduleSync waiting for lock
+	avail        availability
 
 	frontResetTimeout time.Duration
 
@@ -50,6 +53,7 @@ func (self *UI) Init(ctx context.Context) error {
 		return err
 	}
 	self.g.Log.Debugf("menu len=%d", len(self.menu))
+	self.availInit(ctx)
 
 	self.display = self.g.MustTextDisplay()
 	self.eventch = make(chan types.Event)
@@ -60,18 +64,23 @@ func (self *UI) Init(ctx context.Context) error {
 	self.frontResetTimeout = helpers.IntSecondDefault(self.g.Config.UI.Front.ResetTimeoutSec, 0)
 
 	self.lock.ch = make(chan struct{}, 1)
+	self.lockq = engine.NewRunner()
+	go self.lockq.Loop(ctx, self.g.Alive)
 
 	self.Service.Init(ctx)
 	self.g.XXX_uier.Store(types.UIer(self)) // FIXME import cycle traded for pointer cycle
 	return nil
 }
 
+// ScheduleSync runs fun with UI locked, one task at a time in priority order.
 func (self *UI) ScheduleSync(ctx context.Context, priority tele_api.Priority, fun types.TaskFunc) error {
-	if !self.LockWait(priority) {
-		return errors.Trace(types.ErrInterrupted)
-	}
-	defer self.LockDecrementWait()
-	return fun(ctx)
+	return self.lockq.ScheduleSync(ctx, priority, func(ctx context.Context) error {
+		if !self.LockWait(priority) {
+			return errors.Trace(types.ErrInterrupted)
+		}
+		defer self.LockDecrementWait()
+		return fun(ctx)
+	})
 }
 
 func (self *UI) wait(timeout time.Duration) types.Event {
@@ -101,6 +110,13 @@ again:
 		}
 		return types.Event{Kind: types.EventLock}
 
+	case <-self.avail.changed:
+		switch self.State() {
+		case StateFrontBegin, StateFrontSelect, StateFrontTune:
+			return types.Event{Kind: types.EventAvailability}
+		}
+		goto again // next StateFrontBegin checks anyway
+
 	case <-tmr.C:
 		return types.Event{Kind: types.EventTime}
 
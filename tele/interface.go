@@ -19,6 +19,9 @@ type Teler interface {
 	StatModify(func(*Stat))
 	Report(ctx context.Context, serviceTag bool) error
 	Transaction(*Telemetry_Transaction)
+	Job(*Telemetry_Job)
+	Menu(*Telemetry_Menu)
+	Refund(*Telemetry_Refund)
 }
 
 type stub struct{}
@@ -32,5 +35,8 @@ func (stub) Error(error)                                       {}
 func (stub) StatModify(func(*Stat))                            {}
 func (stub) Report(ctx context.Context, serviceTag bool) error { return nil }
 func (stub) Transaction(*Telemetry_Transaction)                {}
+func (stub) Job(*Telemetry_Job)                                {}
+func (stub) Menu(*Telemetry_Menu)                              {}
+func (stub) Refund(*Telemetry_Refund)                          {}
 
 func NewStub() Teler { return stub{} }
@@ -24,3 +24,9 @@ func (Noop) StatModify(func(*Stat)) {}
 func (Noop) Report(ctx context.Context, serviceTag bool) error { return nil }
 
 func (Noop) Transaction(*Telemetry_Transaction) {}
+
+func (Noop) Job(*Telemetry_Job) {}
+
+func (Noop) Menu(*Telemetry_Menu) {}
+
+func (Noop) Refund(*Telemetry_Refund) {}
@@ -44,7 +44,7 @@ func (x Priority) String() string {
 	return proto.EnumName(Priority_name, int32(x))
 }
 func (Priority) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{0}
+	return fileDescriptor_tele_b5008c49cb145087, []int{0}
 }
 
 type State int32
@@ -82,7 +82,7 @@ func (x State) String() string {
 	return proto.EnumName(State_name, int32(x))
 }
 func (State) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1}
 }
 
 type PaymentMethod int32
@@ -111,11 +111,12 @@ func (x PaymentMethod) String() string {
 	return proto.EnumName(PaymentMethod_name, int32(x))
 }
 func (PaymentMethod) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2}
 }
 
 type Inventory struct {
 	Stocks               []*Inventory_StockItem `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	Journal              []*Inventory_Change    `protobuf:"bytes,2,rep,name=journal,proto3" json:"journal,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
 	XXX_unrecognized     []byte                 `json:"-"`
 	XXX_sizecache        int32                  `json:"-"`
@@ -125,7 +126,7 @@ func (m *Inventory) Reset()         { *m = Inventory{} }
 func (m *Inventory) String() string { return proto.CompactTextString(m) }
 func (*Inventory) ProtoMessage()    {}
 func (*Inventory) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{0}
+	return fileDescriptor_tele_b5008c49cb145087, []int{0}
 }
 func (m *Inventory) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Inventory.Unmarshal(m, b)
@@ -152,12 +153,25 @@ func (m *Inventory) GetStocks() []*Inventory_StockItem {
 	return nil
 }
 
+func (m *Inventory) GetJournal() []*Inventory_Change {
+	if m != nil {
+		return m.Journal
+	}
+	return nil
+}
+
 type Inventory_StockItem struct {
 	Code                 uint32   `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Value                int32    `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
 	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	Hopper               int32    `protobuf:"varint,4,opt,name=hopper,proto3" json:"hopper,omitempty"`
 	Valuef               float32  `protobuf:"fixed32,5,opt,name=valuef,proto3" json:"valuef,omitempty"`
+	Rate                 float32  `protobuf:"fixed32,6,opt,name=rate,proto3" json:"rate,omitempty"`
+	ForecastMin          int64    `protobuf:"varint,7,opt,name=forecast_min,json=forecastMin,proto3" json:"forecast_min,omitempty"`
+	Expire               int64    `protobuf:"varint,8,opt,name=expire,proto3" json:"expire,omitempty"`
+	ExpireSoon           bool     `protobuf:"varint,9,opt,name=expire_soon,json=expireSoon,proto3" json:"expire_soon,omitempty"`
+	Capacity             float32  `protobuf:"fixed32,10,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Fill                 float32  `protobuf:"fixed32,11,opt,name=fill,proto3" json:"fill,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -167,7 +181,7 @@ func (m *Inventory_StockItem) Reset()         { *m = Inventory_StockItem{} }
 func (m *Inventory_StockItem) String() string { return proto.CompactTextString(m) }
 func (*Inventory_StockItem) ProtoMessage()    {}
 func (*Inventory_StockItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{0, 0}
+	return fileDescriptor_tele_b5008c49cb145087, []int{0, 0}
 }
 func (m *Inventory_StockItem) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Inventory_StockItem.Unmarshal(m, b)
@@ -222,6 +236,134 @@ func (m *Inventory_StockItem) GetValuef() float32 {
 	return 0
 }
 
+func (m *Inventory_StockItem) GetRate() float32 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *Inventory_StockItem) GetForecastMin() int64 {
+	if m != nil {
+		return m.ForecastMin
+	}
+	return 0
+}
+
+func (m *Inventory_StockItem) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+func (m *Inventory_StockItem) GetExpireSoon() bool {
+	if m != nil {
+		return m.ExpireSoon
+	}
+	return false
+}
+
+func (m *Inventory_StockItem) GetCapacity() float32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *Inventory_StockItem) GetFill() float32 {
+	if m != nil {
+		return m.Fill
+	}
+	return 0
+}
+
+type Inventory_Change struct {
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Code                 uint32   `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Source               string   `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Before               float32  `protobuf:"fixed32,6,opt,name=before,proto3" json:"before,omitempty"`
+	After                float32  `protobuf:"fixed32,7,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Inventory_Change) Reset()         { *m = Inventory_Change{} }
+func (m *Inventory_Change) String() string { return proto.CompactTextString(m) }
+func (*Inventory_Change) ProtoMessage()    {}
+func (*Inventory_Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{0, 1}
+}
+func (m *Inventory_Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Inventory_Change.Unmarshal(m, b)
+}
+func (m *Inventory_Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Inventory_Change.Marshal(b, m, deterministic)
+}
+func (dst *Inventory_Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Inventory_Change.Merge(dst, src)
+}
+func (m *Inventory_Change) XXX_Size() int {
+	return xxx_messageInfo_Inventory_Change.Size(m)
+}
+func (m *Inventory_Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_Inventory_Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Inventory_Change proto.InternalMessageInfo
+
+func (m *Inventory_Change) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Inventory_Change) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *Inventory_Change) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Inventory_Change) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Inventory_Change) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *Inventory_Change) GetBefore() float32 {
+	if m != nil {
+		return m.Before
+	}
+	return 0
+}
+
+func (m *Inventory_Change) GetAfter() float32 {
+	if m != nil {
+		return m.After
+	}
+	return 0
+}
+
 // Optimising for rare, bulk delivery on cell network.
 // "Touching network" is expensive, while 10 or 900 bytes is about same cost.
 type Telemetry struct {
@@ -234,6 +376,9 @@ type Telemetry struct {
 	Stat                 *Telemetry_Stat        `protobuf:"bytes,7,opt,name=stat,proto3" json:"stat,omitempty"`
 	MoneySave            *Telemetry_Money       `protobuf:"bytes,8,opt,name=money_save,json=moneySave,proto3" json:"money_save,omitempty"`
 	MoneyChange          *Telemetry_Money       `protobuf:"bytes,9,opt,name=money_change,json=moneyChange,proto3" json:"money_change,omitempty"`
+	Job                  *Telemetry_Job         `protobuf:"bytes,10,opt,name=job,proto3" json:"job,omitempty"`
+	Menu                 *Telemetry_Menu        `protobuf:"bytes,11,opt,name=menu,proto3" json:"menu,omitempty"`
+	Refund               *Telemetry_Refund      `protobuf:"bytes,12,opt,name=refund,proto3" json:"refund,omitempty"`
 	AtService            bool                   `protobuf:"varint,16,opt,name=at_service,json=atService,proto3" json:"at_service,omitempty"`
 	BuildVersion         string                 `protobuf:"bytes,17,opt,name=build_version,json=buildVersion,proto3" json:"build_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
@@ -245,7 +390,7 @@ func (m *Telemetry) Reset()         { *m = Telemetry{} }
 func (m *Telemetry) String() string { return proto.CompactTextString(m) }
 func (*Telemetry) ProtoMessage()    {}
 func (*Telemetry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1}
 }
 func (m *Telemetry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Telemetry.Unmarshal(m, b)
@@ -328,6 +473,27 @@ func (m *Telemetry) GetMoneyChange() *Telemetry_Money {
 	return nil
 }
 
+func (m *Telemetry) GetJob() *Telemetry_Job {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+func (m *Telemetry) GetMenu() *Telemetry_Menu {
+	if m != nil {
+		return m.Menu
+	}
+	return nil
+}
+
+func (m *Telemetry) GetRefund() *Telemetry_Refund {
+	if m != nil {
+		return m.Refund
+	}
+	return nil
+}
+
 func (m *Telemetry) GetAtService() bool {
 	if m != nil {
 		return m.AtService
@@ -355,7 +521,7 @@ func (m *Telemetry_Error) Reset()         { *m = Telemetry_Error{} }
 func (m *Telemetry_Error) String() string { return proto.CompactTextString(m) }
 func (*Telemetry_Error) ProtoMessage()    {}
 func (*Telemetry_Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1, 0}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 0}
 }
 func (m *Telemetry_Error) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Telemetry_Error.Unmarshal(m, b)
@@ -410,7 +576,7 @@ func (m *Telemetry_Money) Reset()         { *m = Telemetry_Money{} }
 func (m *Telemetry_Money) String() string { return proto.CompactTextString(m) }
 func (*Telemetry_Money) ProtoMessage()    {}
 func (*Telemetry_Money) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1, 1}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 1}
 }
 func (m *Telemetry_Money) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Telemetry_Money.Unmarshal(m, b)
@@ -459,23 +625,26 @@ func (m *Telemetry_Money) GetCoins() map[uint32]uint32 {
 }
 
 type Telemetry_Transaction struct {
-	Code                 string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	Options              []int32       `protobuf:"varint,2,rep,packed,name=options,proto3" json:"options,omitempty"`
-	Price                uint32        `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
-	PaymentMethod        PaymentMethod `protobuf:"varint,4,opt,name=payment_method,json=paymentMethod,proto3,enum=tele.PaymentMethod" json:"payment_method,omitempty"`
-	CreditBills          uint32        `protobuf:"varint,5,opt,name=credit_bills,json=creditBills,proto3" json:"credit_bills,omitempty"`
-	CreditCoins          uint32        `protobuf:"varint,6,opt,name=credit_coins,json=creditCoins,proto3" json:"credit_coins,omitempty"`
-	Spent                *Inventory    `protobuf:"bytes,7,opt,name=spent,proto3" json:"spent,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Code          string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Options       []int32       `protobuf:"varint,2,rep,packed,name=options,proto3" json:"options,omitempty"`
+	Price         uint32        `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	PaymentMethod PaymentMethod `protobuf:"varint,4,opt,name=payment_method,json=paymentMethod,proto3,enum=tele.PaymentMethod" json:"payment_method,omitempty"`
+	CreditBills   uint32        `protobuf:"varint,5,opt,name=credit_bills,json=creditBills,proto3" json:"credit_bills,omitempty"`
+	CreditCoins   uint32        `protobuf:"varint,6,opt,name=credit_coins,json=creditCoins,proto3" json:"credit_coins,omitempty"`
+	Spent         *Inventory    `protobuf:"bytes,7,opt,name=spent,proto3" json:"spent,omitempty"`
+	// set when vend failed, not a sale
+	Error                string           `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	Trace                *Telemetry_Trace `protobuf:"bytes,9,opt,name=trace,proto3" json:"trace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *Telemetry_Transaction) Reset()         { *m = Telemetry_Transaction{} }
 func (m *Telemetry_Transaction) String() string { return proto.CompactTextString(m) }
 func (*Telemetry_Transaction) ProtoMessage()    {}
 func (*Telemetry_Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1, 2}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 2}
 }
 func (m *Telemetry_Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Telemetry_Transaction.Unmarshal(m, b)
@@ -544,21 +713,313 @@ func (m *Telemetry_Transaction) GetSpent() *Inventory {
 	return nil
 }
 
-type Telemetry_Stat struct {
-	Activity             uint32            `protobuf:"varint,1,opt,name=activity,proto3" json:"activity,omitempty"`
-	BillRejected         map[uint32]uint32 `protobuf:"bytes,16,rep,name=bill_rejected,json=billRejected,proto3" json:"bill_rejected,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	CoinRejected         map[uint32]uint32 `protobuf:"bytes,17,rep,name=coin_rejected,json=coinRejected,proto3" json:"coin_rejected,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	CoinSlug             uint32            `protobuf:"varint,18,opt,name=coin_slug,json=coinSlug,proto3" json:"coin_slug,omitempty"`
+func (m *Telemetry_Transaction) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Telemetry_Transaction) GetTrace() *Telemetry_Trace {
+	if m != nil {
+		return m.Trace
+	}
+	return nil
+}
+
+// Execution trace tree, see engine.Span.
+type Telemetry_Trace struct {
+	Name                 string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Args                 string             `protobuf:"bytes,2,opt,name=args,proto3" json:"args,omitempty"`
+	Begin                int64              `protobuf:"varint,3,opt,name=begin,proto3" json:"begin,omitempty"`
+	Duration             int64              `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Error                string             `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Children             []*Telemetry_Trace `protobuf:"bytes,6,rep,name=children,proto3" json:"children,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Telemetry_Trace) Reset()         { *m = Telemetry_Trace{} }
+func (m *Telemetry_Trace) String() string { return proto.CompactTextString(m) }
+func (*Telemetry_Trace) ProtoMessage()    {}
+func (*Telemetry_Trace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 3}
+}
+func (m *Telemetry_Trace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry_Trace.Unmarshal(m, b)
+}
+func (m *Telemetry_Trace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry_Trace.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry_Trace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry_Trace.Merge(dst, src)
+}
+func (m *Telemetry_Trace) XXX_Size() int {
+	return xxx_messageInfo_Telemetry_Trace.Size(m)
+}
+func (m *Telemetry_Trace) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry_Trace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry_Trace proto.InternalMessageInfo
+
+func (m *Telemetry_Trace) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Telemetry_Trace) GetArgs() string {
+	if m != nil {
+		return m.Args
+	}
+	return ""
+}
+
+func (m *Telemetry_Trace) GetBegin() int64 {
+	if m != nil {
+		return m.Begin
+	}
+	return 0
+}
+
+func (m *Telemetry_Trace) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *Telemetry_Trace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Telemetry_Trace) GetChildren() []*Telemetry_Trace {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+// Outcome of periodic job, engine.job config.
+type Telemetry_Job struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Begin                int64    `protobuf:"varint,2,opt,name=begin,proto3" json:"begin,omitempty"`
+	Duration             int64    `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Telemetry_Job) Reset()         { *m = Telemetry_Job{} }
+func (m *Telemetry_Job) String() string { return proto.CompactTextString(m) }
+func (*Telemetry_Job) ProtoMessage()    {}
+func (*Telemetry_Job) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 4}
+}
+func (m *Telemetry_Job) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry_Job.Unmarshal(m, b)
+}
+func (m *Telemetry_Job) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry_Job.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry_Job) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry_Job.Merge(dst, src)
+}
+func (m *Telemetry_Job) XXX_Size() int {
+	return xxx_messageInfo_Telemetry_Job.Size(m)
+}
+func (m *Telemetry_Job) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry_Job.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry_Job proto.InternalMessageInfo
+
+func (m *Telemetry_Job) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Telemetry_Job) GetBegin() int64 {
+	if m != nil {
+		return m.Begin
+	}
+	return 0
+}
+
+func (m *Telemetry_Job) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *Telemetry_Job) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// Menu item codes by availability, sent when it changes.
+type Telemetry_Menu struct {
+	Available            []string `protobuf:"bytes,1,rep,name=available,proto3" json:"available,omitempty"`
+	Unavailable          []string `protobuf:"bytes,2,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Telemetry_Menu) Reset()         { *m = Telemetry_Menu{} }
+func (m *Telemetry_Menu) String() string { return proto.CompactTextString(m) }
+func (*Telemetry_Menu) ProtoMessage()    {}
+func (*Telemetry_Menu) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 5}
+}
+func (m *Telemetry_Menu) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry_Menu.Unmarshal(m, b)
+}
+func (m *Telemetry_Menu) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry_Menu.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry_Menu) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry_Menu.Merge(dst, src)
+}
+func (m *Telemetry_Menu) XXX_Size() int {
+	return xxx_messageInfo_Telemetry_Menu.Size(m)
+}
+func (m *Telemetry_Menu) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry_Menu.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry_Menu proto.InternalMessageInfo
+
+func (m *Telemetry_Menu) GetAvailable() []string {
+	if m != nil {
+		return m.Available
+	}
+	return nil
+}
+
+func (m *Telemetry_Menu) GetUnavailable() []string {
+	if m != nil {
+		return m.Unavailable
+	}
+	return nil
+}
+
+// Customer credit found in persisted state at boot, see money.persist.
+type Telemetry_Refund struct {
+	Credit               uint32            `protobuf:"varint,1,opt,name=credit,proto3" json:"credit,omitempty"`
+	Gift                 uint32            `protobuf:"varint,2,opt,name=gift,proto3" json:"gift,omitempty"`
+	Escrow               uint32            `protobuf:"varint,3,opt,name=escrow,proto3" json:"escrow,omitempty"`
+	Bills                map[uint32]uint32 `protobuf:"bytes,4,rep,name=bills,proto3" json:"bills,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Coins                map[uint32]uint32 `protobuf:"bytes,5,rep,name=coins,proto3" json:"coins,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Time                 int64             `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	Offered              bool              `protobuf:"varint,7,opt,name=offered,proto3" json:"offered,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
 }
 
+func (m *Telemetry_Refund) Reset()         { *m = Telemetry_Refund{} }
+func (m *Telemetry_Refund) String() string { return proto.CompactTextString(m) }
+func (*Telemetry_Refund) ProtoMessage()    {}
+func (*Telemetry_Refund) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 6}
+}
+func (m *Telemetry_Refund) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry_Refund.Unmarshal(m, b)
+}
+func (m *Telemetry_Refund) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry_Refund.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry_Refund) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry_Refund.Merge(dst, src)
+}
+func (m *Telemetry_Refund) XXX_Size() int {
+	return xxx_messageInfo_Telemetry_Refund.Size(m)
+}
+func (m *Telemetry_Refund) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry_Refund.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry_Refund proto.InternalMessageInfo
+
+func (m *Telemetry_Refund) GetCredit() uint32 {
+	if m != nil {
+		return m.Credit
+	}
+	return 0
+}
+
+func (m *Telemetry_Refund) GetGift() uint32 {
+	if m != nil {
+		return m.Gift
+	}
+	return 0
+}
+
+func (m *Telemetry_Refund) GetEscrow() uint32 {
+	if m != nil {
+		return m.Escrow
+	}
+	return 0
+}
+
+func (m *Telemetry_Refund) GetBills() map[uint32]uint32 {
+	if m != nil {
+		return m.Bills
+	}
+	return nil
+}
+
+func (m *Telemetry_Refund) GetCoins() map[uint32]uint32 {
+	if m != nil {
+		return m.Coins
+	}
+	return nil
+}
+
+func (m *Telemetry_Refund) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Telemetry_Refund) GetOffered() bool {
+	if m != nil {
+		return m.Offered
+	}
+	return false
+}
+
+type Telemetry_Stat struct {
+	Activity     uint32            `protobuf:"varint,1,opt,name=activity,proto3" json:"activity,omitempty"`
+	BillRejected map[uint32]uint32 `protobuf:"bytes,16,rep,name=bill_rejected,json=billRejected,proto3" json:"bill_rejected,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	CoinRejected map[uint32]uint32 `protobuf:"bytes,17,rep,name=coin_rejected,json=coinRejected,proto3" json:"coin_rejected,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	CoinSlug     uint32            `protobuf:"varint,18,opt,name=coin_slug,json=coinSlug,proto3" json:"coin_slug,omitempty"`
+	// profiled actions (engine.profile.regexp) by name, since previous Stat
+	Latency              map[string]*Telemetry_Latency `protobuf:"bytes,19,rep,name=latency,proto3" json:"latency,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
 func (m *Telemetry_Stat) Reset()         { *m = Telemetry_Stat{} }
 func (m *Telemetry_Stat) String() string { return proto.CompactTextString(m) }
 func (*Telemetry_Stat) ProtoMessage()    {}
 func (*Telemetry_Stat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{1, 3}
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 7}
 }
 func (m *Telemetry_Stat) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Telemetry_Stat.Unmarshal(m, b)
@@ -606,6 +1067,76 @@ func (m *Telemetry_Stat) GetCoinSlug() uint32 {
 	return 0
 }
 
+func (m *Telemetry_Stat) GetLatency() map[string]*Telemetry_Latency {
+	if m != nil {
+		return m.Latency
+	}
+	return nil
+}
+
+// Microseconds, percentiles are over recent samples.
+type Telemetry_Latency struct {
+	Count                uint32   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	P50                  uint32   `protobuf:"varint,2,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95                  uint32   `protobuf:"varint,3,opt,name=p95,proto3" json:"p95,omitempty"`
+	Max                  uint32   `protobuf:"varint,4,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Telemetry_Latency) Reset()         { *m = Telemetry_Latency{} }
+func (m *Telemetry_Latency) String() string { return proto.CompactTextString(m) }
+func (*Telemetry_Latency) ProtoMessage()    {}
+func (*Telemetry_Latency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{1, 8}
+}
+func (m *Telemetry_Latency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry_Latency.Unmarshal(m, b)
+}
+func (m *Telemetry_Latency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry_Latency.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry_Latency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry_Latency.Merge(dst, src)
+}
+func (m *Telemetry_Latency) XXX_Size() int {
+	return xxx_messageInfo_Telemetry_Latency.Size(m)
+}
+func (m *Telemetry_Latency) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry_Latency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry_Latency proto.InternalMessageInfo
+
+func (m *Telemetry_Latency) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Telemetry_Latency) GetP50() uint32 {
+	if m != nil {
+		return m.P50
+	}
+	return 0
+}
+
+func (m *Telemetry_Latency) GetP95() uint32 {
+	if m != nil {
+		return m.P95
+	}
+	return 0
+}
+
+func (m *Telemetry_Latency) GetMax() uint32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
 type Command struct {
 	Id         uint32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	ReplyTopic string   `protobuf:"bytes,2,opt,name=reply_topic,json=replyTopic,proto3" json:"reply_topic,omitempty"`
@@ -619,6 +1150,7 @@ type Command struct {
 	//	*Command_SetConfig
 	//	*Command_Stop
 	//	*Command_Show_QR
+	//	*Command_Abort
 	Task                 isCommand_Task `protobuf_oneof:"task"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
@@ -629,7 +1161,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2}
 }
 func (m *Command) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command.Unmarshal(m, b)
@@ -709,6 +1241,10 @@ type Command_Show_QR struct {
 	Show_QR *Command_ArgShowQR `protobuf:"bytes,22,opt,name=show_QR,json=showQR,proto3,oneof"`
 }
 
+type Command_Abort struct {
+	Abort *Command_ArgAbort `protobuf:"bytes,23,opt,name=abort,proto3,oneof"`
+}
+
 func (*Command_Report) isCommand_Task() {}
 
 func (*Command_Lock) isCommand_Task() {}
@@ -723,6 +1259,8 @@ func (*Command_Stop) isCommand_Task() {}
 
 func (*Command_Show_QR) isCommand_Task() {}
 
+func (*Command_Abort) isCommand_Task() {}
+
 func (m *Command) GetTask() isCommand_Task {
 	if m != nil {
 		return m.Task
@@ -779,6 +1317,13 @@ func (m *Command) GetShow_QR() *Command_ArgShowQR {
 	return nil
 }
 
+func (m *Command) GetAbort() *Command_ArgAbort {
+	if x, ok := m.GetTask().(*Command_Abort); ok {
+		return x.Abort
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Command) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Command_OneofMarshaler, _Command_OneofUnmarshaler, _Command_OneofSizer, []interface{}{
@@ -789,6 +1334,7 @@ func (*Command) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error
 		(*Command_SetConfig)(nil),
 		(*Command_Stop)(nil),
 		(*Command_Show_QR)(nil),
+		(*Command_Abort)(nil),
 	}
 }
 
@@ -831,6 +1377,11 @@ func _Command_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Show_QR); err != nil {
 			return err
 		}
+	case *Command_Abort:
+		b.EncodeVarint(23<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Abort); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Command.Task has unexpected type %T", x)
@@ -897,6 +1448,14 @@ func _Command_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer
 		err := b.DecodeMessage(msg)
 		m.Task = &Command_Show_QR{msg}
 		return true, err
+	case 23: // task.abort
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Command_ArgAbort)
+		err := b.DecodeMessage(msg)
+		m.Task = &Command_Abort{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -941,6 +1500,11 @@ func _Command_OneofSizer(msg proto.Message) (n int) {
 		n += 2 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Command_Abort:
+		s := proto.Size(x.Abort)
+		n += 2 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -958,7 +1522,7 @@ func (m *Command_ArgReport) Reset()         { *m = Command_ArgReport{} }
 func (m *Command_ArgReport) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgReport) ProtoMessage()    {}
 func (*Command_ArgReport) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 0}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 0}
 }
 func (m *Command_ArgReport) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgReport.Unmarshal(m, b)
@@ -989,7 +1553,7 @@ func (m *Command_ArgLock) Reset()         { *m = Command_ArgLock{} }
 func (m *Command_ArgLock) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgLock) ProtoMessage()    {}
 func (*Command_ArgLock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 1}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 1}
 }
 func (m *Command_ArgLock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgLock.Unmarshal(m, b)
@@ -1028,7 +1592,7 @@ func (m *Command_ArgExec) Reset()         { *m = Command_ArgExec{} }
 func (m *Command_ArgExec) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgExec) ProtoMessage()    {}
 func (*Command_ArgExec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 2}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 2}
 }
 func (m *Command_ArgExec) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgExec.Unmarshal(m, b)
@@ -1073,7 +1637,7 @@ func (m *Command_ArgSetInventory) Reset()         { *m = Command_ArgSetInventory
 func (m *Command_ArgSetInventory) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgSetInventory) ProtoMessage()    {}
 func (*Command_ArgSetInventory) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 3}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 3}
 }
 func (m *Command_ArgSetInventory) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgSetInventory.Unmarshal(m, b)
@@ -1112,7 +1676,7 @@ func (m *Command_ArgSetConfig) Reset()         { *m = Command_ArgSetConfig{} }
 func (m *Command_ArgSetConfig) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgSetConfig) ProtoMessage()    {}
 func (*Command_ArgSetConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 4}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 4}
 }
 func (m *Command_ArgSetConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgSetConfig.Unmarshal(m, b)
@@ -1157,7 +1721,7 @@ func (m *Command_ArgStop) Reset()         { *m = Command_ArgStop{} }
 func (m *Command_ArgStop) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgStop) ProtoMessage()    {}
 func (*Command_ArgStop) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 5}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 5}
 }
 func (m *Command_ArgStop) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgStop.Unmarshal(m, b)
@@ -1196,7 +1760,7 @@ func (m *Command_ArgShowQR) Reset()         { *m = Command_ArgShowQR{} }
 func (m *Command_ArgShowQR) String() string { return proto.CompactTextString(m) }
 func (*Command_ArgShowQR) ProtoMessage()    {}
 func (*Command_ArgShowQR) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{2, 6}
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 6}
 }
 func (m *Command_ArgShowQR) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command_ArgShowQR.Unmarshal(m, b)
@@ -1230,6 +1794,36 @@ func (m *Command_ArgShowQR) GetQrText() string {
 	return ""
 }
 
+type Command_ArgAbort struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Command_ArgAbort) Reset()         { *m = Command_ArgAbort{} }
+func (m *Command_ArgAbort) String() string { return proto.CompactTextString(m) }
+func (*Command_ArgAbort) ProtoMessage()    {}
+func (*Command_ArgAbort) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tele_b5008c49cb145087, []int{2, 7}
+}
+func (m *Command_ArgAbort) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Command_ArgAbort.Unmarshal(m, b)
+}
+func (m *Command_ArgAbort) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Command_ArgAbort.Marshal(b, m, deterministic)
+}
+func (dst *Command_ArgAbort) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Command_ArgAbort.Merge(dst, src)
+}
+func (m *Command_ArgAbort) XXX_Size() int {
+	return xxx_messageInfo_Command_ArgAbort.Size(m)
+}
+func (m *Command_ArgAbort) XXX_DiscardUnknown() {
+	xxx_messageInfo_Command_ArgAbort.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Command_ArgAbort proto.InternalMessageInfo
+
 type Response struct {
 	CommandId            uint32   `protobuf:"varint,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
 	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -1244,7 +1838,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tele_03d38c0c7a407910, []int{3}
+	return fileDescriptor_tele_b5008c49cb145087, []int{3}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -1295,15 +1889,24 @@ func (m *Response) GetINTERNALTopic() string {
 func init() {
 	proto.RegisterType((*Inventory)(nil), "tele.Inventory")
 	proto.RegisterType((*Inventory_StockItem)(nil), "tele.Inventory.StockItem")
+	proto.RegisterType((*Inventory_Change)(nil), "tele.Inventory.Change")
 	proto.RegisterType((*Telemetry)(nil), "tele.Telemetry")
 	proto.RegisterType((*Telemetry_Error)(nil), "tele.Telemetry.Error")
 	proto.RegisterType((*Telemetry_Money)(nil), "tele.Telemetry.Money")
 	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Money.BillsEntry")
 	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Money.CoinsEntry")
 	proto.RegisterType((*Telemetry_Transaction)(nil), "tele.Telemetry.Transaction")
+	proto.RegisterType((*Telemetry_Trace)(nil), "tele.Telemetry.Trace")
+	proto.RegisterType((*Telemetry_Job)(nil), "tele.Telemetry.Job")
+	proto.RegisterType((*Telemetry_Menu)(nil), "tele.Telemetry.Menu")
+	proto.RegisterType((*Telemetry_Refund)(nil), "tele.Telemetry.Refund")
+	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Refund.BillsEntry")
+	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Refund.CoinsEntry")
 	proto.RegisterType((*Telemetry_Stat)(nil), "tele.Telemetry.Stat")
 	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Stat.BillRejectedEntry")
 	proto.RegisterMapType((map[uint32]uint32)(nil), "tele.Telemetry.Stat.CoinRejectedEntry")
+	proto.RegisterMapType((map[string]*Telemetry_Latency)(nil), "tele.Telemetry.Stat.LatencyEntry")
+	proto.RegisterType((*Telemetry_Latency)(nil), "tele.Telemetry.Latency")
 	proto.RegisterType((*Command)(nil), "tele.Command")
 	proto.RegisterType((*Command_ArgReport)(nil), "tele.Command.ArgReport")
 	proto.RegisterType((*Command_ArgLock)(nil), "tele.Command.ArgLock")
@@ -1312,95 +1915,128 @@ func init() {
 	proto.RegisterType((*Command_ArgSetConfig)(nil), "tele.Command.ArgSetConfig")
 	proto.RegisterType((*Command_ArgStop)(nil), "tele.Command.ArgStop")
 	proto.RegisterType((*Command_ArgShowQR)(nil), "tele.Command.ArgShowQR")
+	proto.RegisterType((*Command_ArgAbort)(nil), "tele.Command.ArgAbort")
 	proto.RegisterType((*Response)(nil), "tele.Response")
 	proto.RegisterEnum("tele.Priority", Priority_name, Priority_value)
 	proto.RegisterEnum("tele.State", State_name, State_value)
 	proto.RegisterEnum("tele.PaymentMethod", PaymentMethod_name, PaymentMethod_value)
 }
 
-func init() { proto.RegisterFile("tele.proto", fileDescriptor_tele_03d38c0c7a407910) }
-
-var fileDescriptor_tele_03d38c0c7a407910 = []byte{
-	// 1291 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x6e, 0xdb, 0xc6,
-	0x12, 0x36, 0x25, 0x51, 0x12, 0x47, 0x92, 0x43, 0x6f, 0xfe, 0x78, 0x18, 0x04, 0x47, 0x71, 0x90,
-	0x40, 0x70, 0x70, 0x0c, 0x1c, 0x9f, 0xe0, 0x20, 0x4d, 0xdb, 0x04, 0x89, 0x63, 0xd4, 0x42, 0x12,
-	0x23, 0x59, 0xb9, 0xbd, 0x15, 0x68, 0x72, 0x2d, 0xb1, 0x26, 0xb9, 0x0c, 0x77, 0x25, 0x5b, 0xe8,
-	0x4d, 0x5f, 0xa8, 0x0f, 0x90, 0xcb, 0x3e, 0x47, 0xdf, 0xa4, 0x57, 0xc5, 0xcc, 0x52, 0x3f, 0xb1,
-	0x15, 0x03, 0xb9, 0xdb, 0x99, 0xf9, 0xe6, 0xdb, 0xd9, 0xf9, 0xd9, 0x5d, 0x00, 0x2d, 0x12, 0xb1,
-	0x9b, 0x17, 0x52, 0x4b, 0x56, 0xc3, 0xf5, 0xf6, 0x67, 0x0b, 0x9c, 0x7e, 0x36, 0x15, 0x99, 0x96,
-	0xc5, 0x8c, 0xfd, 0x17, 0xea, 0x4a, 0xcb, 0xf0, 0x4c, 0x79, 0x56, 0xb7, 0xda, 0x6b, 0xed, 0xfd,
-	0x6b, 0x97, 0x1c, 0x16, 0x80, 0xdd, 0x01, 0x5a, 0xfb, 0x5a, 0xa4, 0xbc, 0x04, 0xfa, 0x33, 0x70,
-	0x16, 0x4a, 0xc6, 0xa0, 0x16, 0xca, 0x48, 0x78, 0x56, 0xd7, 0xea, 0x75, 0x38, 0xad, 0xd9, 0x2d,
-	0xb0, 0xa7, 0x41, 0x32, 0x11, 0x5e, 0xa5, 0x6b, 0xf5, 0x6c, 0x6e, 0x04, 0x44, 0x66, 0x41, 0x2a,
-	0xbc, 0x6a, 0xd7, 0xea, 0x39, 0x9c, 0xd6, 0xec, 0x0e, 0xd4, 0xc7, 0x32, 0xcf, 0x45, 0xe1, 0xd5,
-	0x08, 0x5a, 0x4a, 0xa8, 0x27, 0xa7, 0x53, 0xcf, 0xee, 0x5a, 0xbd, 0x0a, 0x2f, 0xa5, 0xed, 0x3f,
-	0x5b, 0xe0, 0x1c, 0x8b, 0x44, 0xa4, 0x42, 0x17, 0x33, 0x76, 0x13, 0xec, 0x69, 0x3a, 0x8c, 0x23,
-	0xda, 0xdc, 0xe6, 0xb5, 0x69, 0xda, 0x8f, 0x70, 0x1b, 0x1d, 0xa7, 0x66, 0xef, 0x2a, 0xa7, 0x35,
-	0x7b, 0x02, 0xb6, 0x28, 0x0a, 0x59, 0xd0, 0xde, 0xad, 0xbd, 0xdb, 0xe6, 0x8c, 0x0b, 0xa2, 0xdd,
-	0x03, 0x34, 0x72, 0x83, 0x61, 0xff, 0x01, 0x27, 0x9e, 0x9f, 0x9e, 0xc2, 0x6a, 0xed, 0xdd, 0xb8,
-	0x94, 0x14, 0xbe, 0x44, 0xb0, 0xe7, 0xd0, 0x49, 0x65, 0x26, 0x66, 0xc3, 0x30, 0x50, 0xe3, 0x13,
-	0x79, 0x41, 0x11, 0xaf, 0xd9, 0xe3, 0x3d, 0x82, 0x78, 0x9b, 0xb0, 0xfb, 0x06, 0xca, 0x7e, 0x84,
-	0x96, 0x2e, 0x82, 0x4c, 0x05, 0xa1, 0x8e, 0x65, 0xe6, 0xd5, 0xc9, 0xf3, 0xde, 0x65, 0xcf, 0xe3,
-	0x25, 0x84, 0xaf, 0xe2, 0x59, 0x0f, 0x6a, 0x4a, 0x07, 0xda, 0x6b, 0x90, 0xdf, 0xad, 0xcb, 0x7e,
-	0x03, 0x1d, 0x68, 0x4e, 0x08, 0xf6, 0x14, 0xc0, 0x04, 0xa9, 0x82, 0xa9, 0xf0, 0x9a, 0xd7, 0x45,
-	0xe8, 0x10, 0x70, 0x10, 0x4c, 0x05, 0x7b, 0x06, 0xed, 0xf2, 0x68, 0xe3, 0x20, 0x1b, 0x09, 0xcf,
-	0xb9, 0xce, 0xaf, 0x65, 0x4e, 0x46, 0x48, 0x76, 0x1f, 0x20, 0xd0, 0x43, 0x25, 0x8a, 0x69, 0x1c,
-	0x0a, 0xcf, 0xed, 0x5a, 0xbd, 0x26, 0x77, 0x02, 0x3d, 0x30, 0x0a, 0xf6, 0x10, 0x3a, 0x27, 0x93,
-	0x38, 0x89, 0x86, 0x53, 0x51, 0x28, 0x3c, 0xf9, 0x16, 0xf5, 0x44, 0x9b, 0x94, 0xbf, 0x18, 0x9d,
-	0xff, 0x16, 0x6c, 0xaa, 0xcb, 0xda, 0x16, 0xf3, 0xa0, 0x91, 0x0a, 0xa5, 0x82, 0x91, 0x29, 0xb4,
-	0xc3, 0xe7, 0x22, 0x36, 0x5f, 0x28, 0x27, 0x99, 0xa6, 0x5a, 0x77, 0xb8, 0x11, 0xfc, 0x3f, 0x2a,
-	0x60, 0x53, 0x9c, 0xec, 0xdf, 0xd0, 0xd2, 0x52, 0x07, 0xc9, 0xf0, 0x24, 0x4e, 0x12, 0x55, 0x92,
-	0x02, 0xa9, 0x5e, 0xa3, 0x66, 0x09, 0x08, 0x65, 0x9c, 0x29, 0xa2, 0x9f, 0x03, 0xf6, 0x51, 0xc3,
-	0xfe, 0x0f, 0xb6, 0xf1, 0xad, 0xd2, 0xc4, 0x74, 0xd7, 0xe6, 0x63, 0x97, 0xc8, 0x0e, 0x32, 0x5d,
-	0xcc, 0xb8, 0x81, 0xa3, 0x9f, 0xa1, 0xac, 0x5d, 0xe7, 0x47, 0x7b, 0x94, 0x7e, 0x04, 0xf7, 0x9f,
-	0x01, 0x2c, 0xc9, 0x98, 0x0b, 0xd5, 0x33, 0x31, 0x2b, 0xe3, 0xc6, 0xe5, 0x97, 0xe3, 0xd6, 0x29,
-	0xc7, 0xed, 0x79, 0xe5, 0x99, 0x85, 0x9e, 0x4b, 0xba, 0x6f, 0xf2, 0xfc, 0xdb, 0x82, 0xd6, 0x4a,
-	0xdf, 0x7d, 0x51, 0x03, 0x67, 0x59, 0x03, 0x99, 0xa3, 0x15, 0x93, 0x54, 0xed, 0xd9, 0x7c, 0x2e,
-	0x22, 0x6f, 0x5e, 0x60, 0xe5, 0xcb, 0x1a, 0x90, 0xc0, 0x9e, 0xc3, 0x66, 0x1e, 0xcc, 0x52, 0x91,
-	0xe9, 0x61, 0x2a, 0xf4, 0x58, 0x46, 0x34, 0x5d, 0x9b, 0x7b, 0x37, 0x4d, 0x22, 0x3e, 0x18, 0xdb,
-	0x7b, 0x32, 0xf1, 0x4e, 0xbe, 0x2a, 0xb2, 0x07, 0xd0, 0x0e, 0x0b, 0x11, 0xc5, 0xba, 0x2c, 0x9b,
-	0x4d, 0xc4, 0x2d, 0xa3, 0x33, 0x75, 0x5b, 0x42, 0x4c, 0x96, 0xeb, 0xab, 0x10, 0x53, 0xb9, 0x47,
-	0x60, 0xab, 0x5c, 0x64, 0xf3, 0x89, 0xb9, 0x32, 0xd6, 0xc6, 0xea, 0xff, 0x55, 0x81, 0x1a, 0x0e,
-	0x0f, 0xf3, 0xa1, 0x89, 0xe7, 0x9f, 0xc6, 0x7a, 0x9e, 0xb6, 0x85, 0xcc, 0xde, 0x42, 0x07, 0x43,
-	0x19, 0x16, 0xe2, 0x57, 0x11, 0x6a, 0x11, 0x79, 0x2e, 0x55, 0xf5, 0xf1, 0xba, 0x29, 0xa4, 0x66,
-	0xe0, 0x25, 0xd0, 0xd4, 0xb6, 0x7d, 0xb2, 0xa2, 0x42, 0x32, 0x0c, 0x7a, 0x49, 0xb6, 0x75, 0x0d,
-	0x19, 0x9e, 0xe5, 0x12, 0x59, 0xb8, 0xa2, 0x62, 0xf7, 0xc0, 0x21, 0x32, 0x95, 0x4c, 0x46, 0x1e,
-	0x33, 0x61, 0xa3, 0x62, 0x90, 0x4c, 0x46, 0xfe, 0x4b, 0xd8, 0xba, 0x12, 0xcc, 0x37, 0x75, 0xc6,
-	0x4b, 0xd8, 0xba, 0x12, 0xc0, 0xb7, 0x10, 0x6c, 0x7f, 0xae, 0x43, 0x63, 0x5f, 0xa6, 0x69, 0x90,
-	0x45, 0x6c, 0x13, 0x2a, 0xe5, 0xf5, 0xdd, 0xe1, 0x95, 0x38, 0xc2, 0xd9, 0x2b, 0x44, 0x9e, 0xcc,
-	0x86, 0x5a, 0xe6, 0x71, 0x58, 0x8e, 0x36, 0x90, 0xea, 0x18, 0x35, 0x58, 0x91, 0x48, 0x04, 0x51,
-	0x12, 0x67, 0xa6, 0xb9, 0xaa, 0x7c, 0x21, 0xb3, 0x1d, 0x68, 0xe6, 0x45, 0x2c, 0x0b, 0xac, 0x96,
-	0xe9, 0xac, 0xcd, 0xb2, 0xb3, 0x4a, 0x2d, 0x5f, 0xd8, 0xf1, 0xd9, 0x2b, 0x44, 0x2e, 0x0b, 0x4d,
-	0x97, 0x53, 0x6b, 0xef, 0xae, 0x41, 0x96, 0x71, 0xed, 0xbe, 0x2a, 0x46, 0x9c, 0xcc, 0x87, 0x1b,
-	0xbc, 0x04, 0xb2, 0x27, 0x50, 0x4b, 0x64, 0x78, 0x46, 0x77, 0xd5, 0xe2, 0x16, 0x5c, 0x71, 0x78,
-	0x27, 0xc3, 0xb3, 0xc3, 0x0d, 0x4e, 0x20, 0x04, 0x8b, 0x0b, 0x11, 0x52, 0xfa, 0xd7, 0x81, 0x0f,
-	0x2e, 0x44, 0x88, 0x60, 0x04, 0xb1, 0x37, 0xd0, 0x51, 0x42, 0x0f, 0x97, 0xaf, 0xce, 0x4d, 0xf2,
-	0xba, 0x7f, 0xc5, 0x6b, 0x20, 0xf4, 0xa2, 0x59, 0x0f, 0x37, 0x78, 0x5b, 0xad, 0xc8, 0xec, 0x7b,
-	0x00, 0x64, 0x09, 0x65, 0x76, 0x1a, 0x8f, 0xbc, 0x5b, 0x44, 0xe1, 0xaf, 0xa3, 0xd8, 0x27, 0xc4,
-	0xe1, 0x06, 0x77, 0xd4, 0x5c, 0xc0, 0x78, 0x95, 0x96, 0xb9, 0x77, 0xfb, 0x2b, 0xf1, 0x0e, 0xb4,
-	0xcc, 0x31, 0x5e, 0x04, 0xb1, 0x3d, 0x68, 0xa8, 0xb1, 0x3c, 0x1f, 0x7e, 0xe4, 0xde, 0x9d, 0xaf,
-	0x64, 0x6f, 0x30, 0x96, 0xe7, 0x1f, 0x39, 0x66, 0x4f, 0xd1, 0xca, 0x6f, 0x81, 0xb3, 0x48, 0xaa,
-	0xff, 0x08, 0x1a, 0x65, 0xc2, 0xa8, 0xa0, 0x93, 0x22, 0xa0, 0xf7, 0xcf, 0x3c, 0xe3, 0x0b, 0xd9,
-	0xff, 0x8e, 0x60, 0x98, 0x2a, 0x84, 0xa9, 0x50, 0x64, 0x41, 0x11, 0xcb, 0xf2, 0x0e, 0x5a, 0xc8,
-	0x78, 0x37, 0x51, 0x61, 0x2a, 0xf4, 0xcc, 0xd0, 0xda, 0x7f, 0x0a, 0x37, 0x2e, 0xe5, 0x8b, 0x3d,
-	0x80, 0x6a, 0x26, 0xce, 0xc9, 0x7b, 0xcd, 0xe8, 0xa3, 0xcd, 0x7f, 0x0a, 0xed, 0xd5, 0x14, 0x2d,
-	0xbe, 0x2c, 0xd6, 0xca, 0x97, 0xc5, 0x35, 0x34, 0xb8, 0x59, 0xdb, 0x78, 0x3d, 0xa4, 0x30, 0x31,
-	0x43, 0x78, 0x25, 0xe2, 0x87, 0x43, 0x4e, 0x74, 0x79, 0x98, 0xb9, 0xe8, 0xff, 0x40, 0xe7, 0x37,
-	0x69, 0xc1, 0xef, 0x4d, 0x12, 0xcc, 0xe6, 0x28, 0x87, 0x97, 0x12, 0xbb, 0x0b, 0x8d, 0x4f, 0xc5,
-	0x50, 0x8b, 0x0b, 0x5d, 0xb6, 0x7e, 0xfd, 0x53, 0x71, 0x2c, 0x2e, 0xf4, 0xeb, 0x3a, 0xd4, 0x74,
-	0xa0, 0xce, 0xb6, 0x7f, 0x83, 0x26, 0x17, 0x2a, 0x97, 0x99, 0xa2, 0x37, 0x36, 0x34, 0x09, 0x1f,
-	0x2e, 0x66, 0xc8, 0x29, 0x35, 0xfd, 0x08, 0x07, 0xd0, 0xfc, 0x79, 0x0c, 0x53, 0xf9, 0xb9, 0x61,
-	0x50, 0x8b, 0x02, 0x1d, 0xcc, 0x3f, 0x61, 0xb8, 0x66, 0x8f, 0x61, 0xb3, 0x7f, 0x74, 0x7c, 0xc0,
-	0x8f, 0x5e, 0xbd, 0x2b, 0xe7, 0xee, 0x77, 0x97, 0xcc, 0x9d, 0xb9, 0x9a, 0x66, 0x6f, 0xe7, 0x05,
-	0x34, 0xe7, 0x93, 0xc4, 0x5a, 0xd0, 0x78, 0x23, 0x4e, 0x83, 0x49, 0xa2, 0xdd, 0x0d, 0xd6, 0x80,
-	0xea, 0x91, 0x3c, 0x77, 0x2d, 0xb6, 0x09, 0xd0, 0x8f, 0x12, 0x71, 0x90, 0x8d, 0xe2, 0x4c, 0xb8,
-	0x15, 0xd6, 0x86, 0x26, 0xca, 0x3f, 0x2b, 0x51, 0xb8, 0xb5, 0x9d, 0x00, 0x6c, 0xbc, 0xbf, 0x04,
-	0x3a, 0xf7, 0xb3, 0x69, 0x90, 0xc4, 0x91, 0xbb, 0xc1, 0x9a, 0x50, 0x7b, 0x2d, 0xa5, 0x76, 0x2d,
-	0x54, 0x1f, 0xc9, 0x34, 0xce, 0x82, 0xc4, 0xad, 0x30, 0x17, 0xda, 0x6f, 0x62, 0x15, 0xca, 0x2c,
-	0xa3, 0x6b, 0xc6, 0xad, 0xa2, 0xf9, 0x43, 0x21, 0x4f, 0x12, 0x91, 0xba, 0x35, 0x14, 0xca, 0xcf,
-	0x84, 0x6b, 0x23, 0x05, 0xf6, 0x92, 0x5b, 0xdf, 0x79, 0x01, 0x9d, 0x2f, 0x9e, 0x11, 0xc3, 0xa9,
-	0xc7, 0x71, 0x36, 0x32, 0x5b, 0xe1, 0xcf, 0xcb, 0xb5, 0x30, 0x30, 0x5c, 0x25, 0x42, 0x29, 0xb7,
-	0x82, 0xfa, 0x9f, 0xe2, 0x53, 0xed, 0x56, 0x4f, 0xea, 0xf4, 0x51, 0xfe, 0xdf, 0x3f, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0xd5, 0xe3, 0x7a, 0x05, 0x36, 0x0b, 0x00, 0x00,
+func init() { proto.RegisterFile("tele.proto", fileDescriptor_tele_b5008c49cb145087) }
+
+var fileDescriptor_tele_b5008c49cb145087 = []byte{
+	// 1804 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4f, 0x73, 0xdb, 0xc6,
+	0x15, 0x17, 0x41, 0x82, 0x24, 0x1e, 0x49, 0x05, 0x5e, 0x3b, 0x36, 0x8a, 0x34, 0x53, 0xda, 0x19,
+	0x67, 0x34, 0xce, 0x44, 0x93, 0xa8, 0x4e, 0xe3, 0x38, 0x6d, 0x32, 0xb6, 0xac, 0xd6, 0x4a, 0x6c,
+	0x4f, 0xb2, 0x54, 0x7a, 0xe5, 0x2c, 0xc1, 0x25, 0x05, 0x0b, 0xdc, 0x45, 0x16, 0x4b, 0x4a, 0x9c,
+	0x5e, 0xfa, 0x35, 0x7a, 0xed, 0xad, 0x9d, 0xe9, 0xa1, 0x1f, 0xa0, 0xdf, 0xa5, 0x5f, 0xa0, 0xc7,
+	0xde, 0x3b, 0x6f, 0x77, 0x01, 0xc2, 0x12, 0xe5, 0x19, 0x9f, 0x7a, 0x7b, 0x7f, 0x7e, 0xef, 0xe1,
+	0xed, 0xdb, 0xf7, 0x67, 0x49, 0x00, 0xcd, 0x33, 0xbe, 0x9f, 0x2b, 0xa9, 0x25, 0x69, 0x21, 0x7d,
+	0xef, 0x9f, 0x2d, 0x08, 0x8e, 0xc5, 0x8a, 0x0b, 0x2d, 0xd5, 0x9a, 0x7c, 0x0e, 0xed, 0x42, 0xcb,
+	0xe4, 0xac, 0x88, 0x1a, 0xc3, 0xe6, 0x5e, 0xef, 0xe0, 0x17, 0xfb, 0xc6, 0xa0, 0x02, 0xec, 0x8f,
+	0x50, 0x7b, 0xac, 0xf9, 0x82, 0x3a, 0x20, 0xf9, 0x0c, 0x3a, 0xaf, 0xe5, 0x52, 0x09, 0x96, 0x45,
+	0x9e, 0xb1, 0xb9, 0x7d, 0xd9, 0xe6, 0xf0, 0x94, 0x89, 0x39, 0xa7, 0x25, 0x2c, 0xfe, 0x8b, 0x07,
+	0x41, 0xe5, 0x87, 0x10, 0x68, 0x25, 0x72, 0xca, 0xa3, 0xc6, 0xb0, 0xb1, 0x37, 0xa0, 0x86, 0x26,
+	0xb7, 0xc0, 0x5f, 0xb1, 0x6c, 0xc9, 0x23, 0x6f, 0xd8, 0xd8, 0xf3, 0xa9, 0x65, 0x10, 0x29, 0xd8,
+	0x82, 0x47, 0xcd, 0x61, 0x63, 0x2f, 0xa0, 0x86, 0x26, 0xb7, 0xa1, 0x7d, 0x2a, 0xf3, 0x9c, 0xab,
+	0xa8, 0x65, 0xa0, 0x8e, 0x43, 0xb9, 0x31, 0x9a, 0x45, 0xfe, 0xb0, 0xb1, 0xe7, 0x51, 0xc7, 0xa1,
+	0x0f, 0xc5, 0x34, 0x8f, 0xda, 0x46, 0x6a, 0x68, 0x72, 0x17, 0xfa, 0x33, 0xa9, 0x78, 0xc2, 0x0a,
+	0x3d, 0x5e, 0xa4, 0x22, 0xea, 0x0c, 0x1b, 0x7b, 0x4d, 0xda, 0x2b, 0x65, 0x2f, 0x53, 0x81, 0xee,
+	0xf8, 0x45, 0x9e, 0x2a, 0x1e, 0x75, 0x8d, 0xd2, 0x71, 0xe4, 0x57, 0xd0, 0xb3, 0xd4, 0xb8, 0x90,
+	0x52, 0x44, 0xc1, 0xb0, 0xb1, 0xd7, 0xa5, 0x60, 0x45, 0x23, 0x29, 0x05, 0x89, 0xa1, 0x9b, 0xb0,
+	0x9c, 0x25, 0xa9, 0x5e, 0x47, 0x60, 0xbe, 0x59, 0xf1, 0x18, 0xcb, 0x2c, 0xcd, 0xb2, 0xa8, 0x67,
+	0x63, 0x41, 0x3a, 0xfe, 0x6b, 0x03, 0xda, 0x36, 0x5f, 0xa8, 0xd6, 0xe9, 0xc2, 0x26, 0xa6, 0x49,
+	0x0d, 0x5d, 0x25, 0xcb, 0xab, 0x25, 0xeb, 0x9a, 0xb4, 0x28, 0xce, 0x0a, 0x29, 0x4c, 0x5a, 0x02,
+	0xea, 0x38, 0x94, 0x17, 0x72, 0xa9, 0x12, 0x6e, 0xd2, 0x12, 0x50, 0xc7, 0xa1, 0x7c, 0xc2, 0xf1,
+	0xc0, 0x2e, 0x31, 0x8e, 0xc3, 0x8b, 0x60, 0x33, 0xcd, 0x95, 0xc9, 0x89, 0x47, 0x2d, 0x73, 0xef,
+	0x6f, 0xb7, 0x20, 0x38, 0xe1, 0x19, 0x5f, 0x70, 0xad, 0xd6, 0xe4, 0x26, 0xf8, 0xab, 0xc5, 0x38,
+	0x9d, 0x9a, 0x40, 0x7d, 0xda, 0x5a, 0x2d, 0x8e, 0xa7, 0x55, 0xf0, 0x5e, 0x2d, 0xf8, 0x4f, 0xc0,
+	0xe7, 0x4a, 0x49, 0x65, 0x22, 0xed, 0x1d, 0xbc, 0x6f, 0xeb, 0xa4, 0x72, 0xb4, 0x7f, 0x84, 0x4a,
+	0x6a, 0x31, 0xe4, 0x53, 0x08, 0xd2, 0xb2, 0x82, 0xcc, 0x21, 0x7a, 0x07, 0xef, 0x5d, 0x2a, 0x2c,
+	0xba, 0x41, 0x90, 0xc7, 0x30, 0x58, 0x48, 0xc1, 0xd7, 0xe3, 0x84, 0x15, 0xa7, 0x13, 0x79, 0x11,
+	0xf9, 0xdb, 0xbf, 0xf1, 0x12, 0x41, 0xb4, 0x6f, 0xb0, 0x87, 0x16, 0x4a, 0x7e, 0x07, 0x3d, 0xad,
+	0x98, 0x28, 0x58, 0xa2, 0x53, 0x29, 0x4c, 0x06, 0x7a, 0x07, 0x1f, 0x5c, 0xb6, 0x3c, 0xd9, 0x40,
+	0x68, 0x1d, 0x4f, 0xf6, 0xa0, 0x55, 0x68, 0xa6, 0x4d, 0x8a, 0x7a, 0x07, 0xb7, 0x2e, 0xdb, 0x8d,
+	0x34, 0xd3, 0xd4, 0x20, 0xc8, 0x43, 0x00, 0x1b, 0x64, 0xc1, 0x56, 0xb6, 0x92, 0xae, 0x8d, 0x30,
+	0x30, 0xc0, 0x11, 0x5b, 0x71, 0xf2, 0x08, 0xfa, 0xee, 0x68, 0xa6, 0x2e, 0xa2, 0xe0, 0x6d, 0x76,
+	0x3d, 0x7b, 0x32, 0x5b, 0x41, 0xf7, 0xa1, 0xf9, 0x5a, 0x4e, 0x4c, 0xdd, 0xf5, 0x0e, 0x6e, 0x5e,
+	0x36, 0xf8, 0x4e, 0x4e, 0x28, 0xea, 0xf1, 0x00, 0x0b, 0x2e, 0x96, 0x51, 0x6f, 0xfb, 0x01, 0x5e,
+	0x72, 0xb1, 0xa4, 0x06, 0x41, 0xf6, 0xb1, 0xac, 0x66, 0x4b, 0x31, 0x8d, 0xfa, 0xc3, 0xc6, 0xa6,
+	0xd5, 0x37, 0x58, 0x6a, 0xb4, 0xd4, 0xa1, 0xc8, 0x87, 0x00, 0x4c, 0x8f, 0x0b, 0xae, 0x56, 0x69,
+	0xc2, 0xa3, 0xd0, 0x74, 0x47, 0xc0, 0xf4, 0xc8, 0x0a, 0xc8, 0x47, 0x30, 0x98, 0x2c, 0xd3, 0x6c,
+	0x3a, 0x5e, 0x71, 0x55, 0x60, 0xea, 0x6f, 0x98, 0xa2, 0xec, 0x1b, 0xe1, 0x1f, 0xad, 0x2c, 0xfe,
+	0x1e, 0x7c, 0x53, 0x18, 0x5b, 0x07, 0x45, 0x04, 0x9d, 0x05, 0x2f, 0x0a, 0x36, 0xb7, 0x95, 0x16,
+	0xd0, 0x92, 0xc5, 0xca, 0x4d, 0xe4, 0x52, 0x68, 0x53, 0x6c, 0x03, 0x6a, 0x99, 0xf8, 0x1f, 0x1e,
+	0xf8, 0x26, 0x51, 0xd8, 0xb9, 0x5a, 0x6a, 0x96, 0x8d, 0x27, 0x69, 0x96, 0x15, 0xce, 0x29, 0x18,
+	0xd1, 0x53, 0x94, 0x6c, 0x00, 0x89, 0x4c, 0x45, 0x11, 0x79, 0x35, 0xc0, 0x21, 0x4a, 0xc8, 0x6f,
+	0xc0, 0xb7, 0xb6, 0x4d, 0x33, 0xf6, 0x86, 0x5b, 0x2f, 0x64, 0xdf, 0x38, 0x3b, 0x12, 0x5a, 0xad,
+	0xa9, 0x85, 0xa3, 0x9d, 0x75, 0xd9, 0x7a, 0x9b, 0x9d, 0xf9, 0x86, 0xb3, 0x33, 0xf0, 0xf8, 0x11,
+	0xc0, 0xc6, 0x19, 0x09, 0xa1, 0x79, 0xc6, 0xd7, 0x2e, 0x6e, 0x24, 0xdf, 0x1c, 0x9a, 0x03, 0x37,
+	0x34, 0x1f, 0x7b, 0x8f, 0x1a, 0x68, 0xb9, 0x71, 0xf7, 0x4e, 0x96, 0xff, 0xf2, 0xa0, 0x57, 0x2b,
+	0xfc, 0x37, 0xee, 0x20, 0xd8, 0xdc, 0x81, 0xcc, 0x51, 0x5b, 0x98, 0x05, 0xe0, 0xd3, 0x92, 0x45,
+	0xbf, 0xb9, 0xc2, 0x9b, 0x77, 0x77, 0x60, 0x18, 0xf2, 0x18, 0x76, 0x73, 0xb6, 0x5e, 0x70, 0xa1,
+	0xc7, 0x0b, 0xae, 0x4f, 0xe5, 0xd4, 0xb4, 0xf7, 0x6e, 0x59, 0xa0, 0x3f, 0x58, 0xdd, 0x4b, 0xa3,
+	0xa2, 0x83, 0xbc, 0xce, 0xe2, 0xa8, 0x4e, 0x14, 0x9f, 0xa6, 0xda, 0x5d, 0x9b, 0x6f, 0x1c, 0xf7,
+	0xac, 0xcc, 0xde, 0xdb, 0x06, 0x62, 0xb3, 0xdc, 0xae, 0x43, 0xec, 0xcd, 0xdd, 0x07, 0xbf, 0xc8,
+	0xb9, 0x28, 0x5b, 0xf6, 0xca, 0x5c, 0xb1, 0x5a, 0x0c, 0xdf, 0xce, 0xab, 0xae, 0x39, 0xad, 0x65,
+	0x70, 0x8a, 0x69, 0xc5, 0x92, 0x6b, 0xfb, 0xf0, 0x04, 0x95, 0xd4, 0x62, 0xe2, 0xbf, 0x37, 0xc0,
+	0x37, 0x82, 0x6a, 0x4a, 0x37, 0x6a, 0x53, 0x9a, 0x40, 0x8b, 0xa9, 0x79, 0xe1, 0x4a, 0xd7, 0xd0,
+	0xf8, 0xd1, 0x09, 0x9f, 0xa7, 0xc2, 0xe4, 0xac, 0x49, 0x2d, 0x83, 0x6b, 0x64, 0xba, 0x54, 0xcc,
+	0xcc, 0xa7, 0x96, 0x51, 0x54, 0xfc, 0x26, 0x4c, 0xbf, 0x1e, 0xe6, 0xe7, 0xd0, 0x4d, 0x4e, 0xd3,
+	0x6c, 0xaa, 0x38, 0x4e, 0xb4, 0xe6, 0xf5, 0x91, 0x56, 0xb0, 0x98, 0x41, 0xf3, 0x3b, 0x39, 0xd9,
+	0x1a, 0x69, 0x15, 0x95, 0x77, 0x5d, 0x54, 0xcd, 0xeb, 0xa2, 0x6a, 0xd5, 0xa2, 0x8a, 0x7f, 0x0f,
+	0x2d, 0x1c, 0x27, 0xe4, 0x97, 0x10, 0xb0, 0x15, 0x4b, 0x33, 0x36, 0xc9, 0xb8, 0x79, 0x6a, 0x04,
+	0x74, 0x23, 0x20, 0x43, 0xe8, 0x2d, 0xc5, 0x46, 0xef, 0x19, 0x7d, 0x5d, 0x14, 0xff, 0xdb, 0x83,
+	0xb6, 0x9d, 0x35, 0xb8, 0xba, 0xec, 0xdd, 0xba, 0x8a, 0x76, 0x1c, 0x1e, 0x63, 0x9e, 0xce, 0x74,
+	0xb9, 0x2a, 0x91, 0x46, 0x2c, 0x2f, 0x12, 0x25, 0xcf, 0x5d, 0x45, 0x3a, 0x8e, 0x7c, 0x59, 0xb6,
+	0xb2, 0x6d, 0xc9, 0xbb, 0xdb, 0xc7, 0xda, 0x96, 0x5e, 0xfe, 0xb2, 0xec, 0x65, 0xff, 0xad, 0x86,
+	0x57, 0x9a, 0xb9, 0xda, 0x8f, 0xed, 0xda, 0x7e, 0xc4, 0x46, 0x9a, 0xcd, 0xb8, 0xe2, 0x53, 0x53,
+	0x98, 0x5d, 0x5a, 0xb2, 0xff, 0x97, 0xd6, 0xff, 0x4f, 0x13, 0x5a, 0xb8, 0xbb, 0xf0, 0x96, 0xb1,
+	0xfb, 0x57, 0xa9, 0x2e, 0x2d, 0x2b, 0x9e, 0x7c, 0x0f, 0x03, 0x4c, 0xc4, 0x58, 0xf1, 0xd7, 0x3c,
+	0xd1, 0x7c, 0x1a, 0x85, 0x26, 0x0f, 0x1f, 0x6f, 0x5b, 0x82, 0x26, 0x7d, 0xd4, 0x01, 0x6d, 0x32,
+	0xfa, 0x93, 0x9a, 0x08, 0x9d, 0x61, 0x72, 0x36, 0xce, 0x6e, 0xbc, 0xc5, 0x19, 0x9e, 0xea, 0x92,
+	0xb3, 0xa4, 0x26, 0x22, 0x1f, 0x40, 0x60, 0x9c, 0x15, 0xd9, 0x72, 0x1e, 0x11, 0x1b, 0x36, 0x0a,
+	0x46, 0xd9, 0x72, 0x4e, 0xbe, 0x86, 0x4e, 0xc6, 0x34, 0x17, 0xc9, 0x3a, 0xba, 0xb9, 0xfd, 0xe2,
+	0xcc, 0x37, 0x5e, 0x58, 0x8c, 0x75, 0x5f, 0x5a, 0xc4, 0xdf, 0xc2, 0x8d, 0x2b, 0x27, 0x79, 0xa7,
+	0xcc, 0x7e, 0x0b, 0x37, 0xae, 0x44, 0xff, 0x4e, 0x0e, 0x46, 0xd0, 0xaf, 0x87, 0x56, 0xb7, 0x0d,
+	0xac, 0xed, 0xa7, 0x75, 0xdb, 0xde, 0xc1, 0x9d, 0xcb, 0xc7, 0x73, 0xe6, 0x75, 0xa7, 0x3f, 0x41,
+	0xc7, 0x49, 0x37, 0xbb, 0xb3, 0x51, 0xdb, 0x9d, 0xf8, 0x95, 0xfc, 0x8b, 0xcf, 0x5c, 0x34, 0x48,
+	0x1a, 0xc9, 0x57, 0x5f, 0xb8, 0x5e, 0x42, 0x12, 0x25, 0x0b, 0x76, 0x61, 0x7a, 0x7e, 0x40, 0x91,
+	0xbc, 0xf7, 0xdf, 0x36, 0x74, 0x0e, 0xe5, 0x62, 0xc1, 0xc4, 0x94, 0xec, 0x82, 0xe7, 0x9e, 0x89,
+	0x03, 0xea, 0xa5, 0x53, 0x5c, 0xb1, 0x8a, 0xe7, 0xd9, 0x7a, 0xac, 0x65, 0x9e, 0x26, 0x6e, 0x0c,
+	0x82, 0x11, 0x9d, 0xa0, 0xc4, 0x0c, 0x18, 0xce, 0xa6, 0x59, 0x2a, 0x78, 0x35, 0x60, 0x1c, 0x4f,
+	0x1e, 0x40, 0x37, 0x57, 0xa9, 0x54, 0x58, 0x96, 0x76, 0x81, 0xec, 0xba, 0x05, 0xe2, 0xa4, 0xb4,
+	0xd2, 0xe3, 0xcf, 0x1a, 0xc5, 0x73, 0xa9, 0x74, 0x14, 0xd6, 0xf3, 0xe1, 0xe2, 0xda, 0x7f, 0xa2,
+	0xe6, 0xd4, 0xa8, 0x9f, 0xef, 0x50, 0x07, 0x24, 0x9f, 0x40, 0x2b, 0x93, 0xc9, 0x99, 0x79, 0x92,
+	0x54, 0xb3, 0xb3, 0x66, 0xf0, 0x42, 0x26, 0x67, 0xcf, 0x77, 0xa8, 0x01, 0x21, 0x98, 0x5f, 0xf0,
+	0x24, 0x22, 0xd7, 0x80, 0x8f, 0x2e, 0x78, 0x82, 0x60, 0x04, 0x91, 0x67, 0x30, 0x28, 0xb8, 0x1e,
+	0x6f, 0x5e, 0xb7, 0x37, 0x8d, 0xd5, 0x87, 0x57, 0xac, 0x46, 0x5c, 0x57, 0x3b, 0xe9, 0xf9, 0x0e,
+	0xed, 0x17, 0x35, 0x9e, 0x7c, 0x0d, 0x80, 0x5e, 0x12, 0x29, 0x66, 0xe9, 0x3c, 0xba, 0x65, 0x5c,
+	0xc4, 0xdb, 0x5c, 0x1c, 0x1a, 0xc4, 0xf3, 0x1d, 0x1a, 0x14, 0x25, 0x83, 0xf1, 0x16, 0x5a, 0xe6,
+	0xd1, 0xfb, 0xd7, 0xc4, 0x3b, 0xd2, 0x32, 0xc7, 0x78, 0x11, 0x44, 0x0e, 0xa0, 0x53, 0x9c, 0xca,
+	0xf3, 0xf1, 0x8f, 0x34, 0xba, 0x7d, 0x4d, 0xf6, 0x46, 0xa7, 0xf2, 0xfc, 0x47, 0x8a, 0xd9, 0x2b,
+	0x0c, 0x45, 0xf6, 0xc1, 0x67, 0x13, 0xcc, 0xf7, 0x9d, 0xfa, 0x3b, 0xb1, 0x66, 0xf1, 0x64, 0x62,
+	0xd3, 0x6d, 0x61, 0x71, 0x0f, 0x82, 0xea, 0x12, 0xe2, 0xfb, 0xd0, 0x71, 0x09, 0x7e, 0x63, 0xc3,
+	0xd8, 0x9f, 0x17, 0x15, 0x1f, 0x7f, 0x05, 0x1d, 0x97, 0x5a, 0x84, 0x15, 0x09, 0x17, 0x4c, 0xa5,
+	0xd2, 0x75, 0x41, 0xc5, 0xe3, 0xa4, 0x35, 0x17, 0xe9, 0x99, 0x91, 0x6a, 0xe8, 0xf8, 0x21, 0xbc,
+	0x77, 0x29, 0xbf, 0xe4, 0x2e, 0x34, 0x05, 0x3f, 0x8f, 0x1a, 0xdb, 0x5f, 0x04, 0xa8, 0x8b, 0x1f,
+	0x42, 0xbf, 0x9e, 0xd2, 0xad, 0x8b, 0x32, 0xb4, 0x6e, 0xf0, 0x63, 0x7d, 0x6b, 0xf5, 0x11, 0x74,
+	0x5c, 0x46, 0x71, 0xc0, 0xe3, 0xa0, 0x97, 0x4b, 0xed, 0x0e, 0x53, 0xb2, 0xf1, 0x6f, 0x21, 0xa8,
+	0xd2, 0x88, 0x5b, 0x2a, 0x63, 0xeb, 0x12, 0x15, 0x50, 0xc7, 0x91, 0x3b, 0xd0, 0xf9, 0x59, 0x8d,
+	0x35, 0xbf, 0xd0, 0xae, 0x55, 0xda, 0x3f, 0xab, 0x13, 0x7e, 0xa1, 0x63, 0x80, 0x6e, 0x99, 0xd2,
+	0xa7, 0x6d, 0x68, 0x69, 0x56, 0x9c, 0xdd, 0xfb, 0x13, 0x74, 0x29, 0x2f, 0x72, 0x29, 0x0a, 0x8e,
+	0xcf, 0xf0, 0xc4, 0xa6, 0x7e, 0x5c, 0xf5, 0x5f, 0xe0, 0x24, 0xc7, 0xd3, 0xcd, 0xaa, 0xf6, 0xea,
+	0x0f, 0x08, 0x02, 0xad, 0x29, 0xd3, 0xac, 0xfc, 0x59, 0x89, 0x34, 0xf9, 0x18, 0x76, 0x8f, 0x5f,
+	0x9d, 0x1c, 0xd1, 0x57, 0x4f, 0x5e, 0xb8, 0x9e, 0xfd, 0x73, 0x68, 0xd4, 0x83, 0x52, 0x6c, 0xfa,
+	0xf6, 0xc1, 0x37, 0xd0, 0x2d, 0xbb, 0x90, 0xf4, 0xa0, 0xf3, 0x8c, 0xcf, 0xd8, 0x32, 0xd3, 0xe1,
+	0x0e, 0xe9, 0x40, 0xf3, 0x95, 0x3c, 0x0f, 0x1b, 0x64, 0x17, 0xe0, 0x78, 0x9a, 0xf1, 0x23, 0x31,
+	0x4f, 0x05, 0x0f, 0x3d, 0xd2, 0x87, 0x2e, 0xf2, 0x3f, 0x15, 0x5c, 0x85, 0xad, 0x07, 0x0c, 0x7c,
+	0x1c, 0xc0, 0x1c, 0x8d, 0x8f, 0xc5, 0x8a, 0x65, 0xe9, 0x34, 0xdc, 0x21, 0x5d, 0x68, 0x3d, 0x95,
+	0x52, 0x87, 0x0d, 0x14, 0xbf, 0x92, 0x8b, 0x54, 0xb0, 0x2c, 0xf4, 0x48, 0x08, 0xfd, 0x67, 0x69,
+	0x91, 0x48, 0x21, 0xcc, 0x38, 0x0d, 0x9b, 0xa8, 0xfe, 0x41, 0xc9, 0x49, 0xc6, 0x17, 0x61, 0x0b,
+	0x19, 0xf7, 0x7b, 0x23, 0xf4, 0xd1, 0x05, 0xd6, 0x55, 0xd8, 0x7e, 0xf0, 0x0d, 0x0c, 0xde, 0x78,
+	0x69, 0x5a, 0x9f, 0xfa, 0x34, 0x15, 0x73, 0xfb, 0x29, 0xfc, 0x75, 0x18, 0x36, 0x30, 0x30, 0xa4,
+	0x32, 0x5e, 0x14, 0xa1, 0x87, 0xf2, 0x3f, 0xa4, 0x33, 0x1d, 0x36, 0x27, 0x6d, 0xf3, 0x27, 0xca,
+	0xaf, 0xff, 0x37, 0x00, 0xc5, 0x05, 0x81, 0xc7, 0x52, 0x11, 0x00, 0x00,
 }
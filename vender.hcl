@@ -8,7 +8,27 @@ engine {
 
   // alias "conveyor_hopper18" { scenario = "evend.conveyor.move(1210)" }
 
+  // Action arguments: `foo(1)`, positional `evend.conveyor.move(1210,200)` or named `evend.conveyor.move(pos=1210,speed=200)`.
+  // Alias `name(?)` passes all arguments to first placeholder in its scenario.
+  // Scenario branches: `if(cond) ... else ... end`, else is optional, `if(!cond)` negates.
+  // Conditions: `stock.{name}.has(x)`, `{device}.ready`, `money.price_ge(x)`, `money.price_lt(x)`.
+  // alias "milk_or_cream" { scenario = "if(stock.milk.has(10)) add.milk(10) else add.cream(5) end" }
+  // Scenario `par(a b c)` runs actions concurrently and waits for all, useful with different devices.
+  // alias "prepare" { scenario = "par(mixer_move_top conveyor_move_cup)" }
+  // Scenario `retry(N, a b)` repeats actions after error, up to N attempts total.
+  // Scenario `timeout(5s, a b)` cancels actions running longer than duration.
+  // alias "cup_drop" { scenario = "retry(3, evend.cup.dispense)" }
+  // alias "mixer_move_top" { scenario = "timeout(5s, evend.mixer.move(100))" }
+  // Alias `undo` is compensation: if vend fails later, undo of completed steps run in reverse order, before on_menu_error.
+  // alias "cup_drop" { scenario = "evend.cup.dispense" undo = "cup_serve" }
+  // Arguments may be integer expressions with variables: `add.water_hot(${water} * 12 / 10)`, operators + - * / %.
+  // Variables are defined in `vars` here (defaults) and in menu item, `${cream}` and `${sugar}` are UI options.
+  // alias "make_tea" { scenario = "cup_drop add.water_hot(${water}) add.sugar(${sugar})" }
+  // vars { water = 150 }
+
   inventory {
+    // Store stock values and append every change to `{persist.root}/inventory-journal`.
+    // Journal: `/journal` in `vender engine-cli`, changes since last report in telemetry.
     persist = true
 
     // Send stock name to telemetry; false to save network usage
@@ -16,16 +36,34 @@ engine {
 
     // Stock fields:
     // - name string, must be non-empty and unique
-    // - code uint32, default=0, sorting index in service menu, duplicates produce warning at boot but allowed
+    // - code uint32, default=0, sorting index in service menu, duplicates produce warning at boot but allowed;
+    //   unique non-zero code keeps persisted value when stock is renamed
     // - check bool, default=false, validate stock remainder > `min`
     // - min float, only makes sense together with check
+    // - capacity float, full stock value; service inventory screen shows fill percent,
+    //   key sugar+ refills by typed amount or to capacity without input
     // - hw_rate float, default=1, engine `add.{name}(x)` sends x*hw_rate to hardware device
+    // - hw_table [[stock, hw]...], overrides hw_rate for non-linear devices, hardware gets value interpolated between points
+    //   Calibrate in `vender engine-cli`: `stock.{name}.calibrate(hw)` runs hardware with raw value,
+    //   `stock.{name}.measured(x)` records actual output, repeat for several doses,
+    //   `stock.{name}.calibration` applies table and prints config line to paste here.
+    //   Calibrated table is persisted and overrides hw_table here until next calibration.
     // - spend_rate float, default=1, engine `stock.{name}.spend(x)` (implied by add) subtracts x*spend_rate from remainder
     // - register_add string, registers `add.{name}(?)` in engine with this scenario, must contain `foo(?)` arg placeholder
+    // - shelf_life duration, "72h", every refill (value increase) is a lot expiring after shelf_life,
+    //   lots are spent first in first out, any expired lot makes menu items with this stock unavailable
+    // - expire_warn duration, default "24h", telemetry flags stock expiring within this time
     // stock "water" { hw_rate = 0.649999805 }
-    // stock "cup" { code = 1 }
+    // stock "water" { hw_table = [[10, 7], [100, 65], [300, 198]] }
+    // stock "cup" { code = 1 capacity = 300 }
+    // stock "milk_powder" { code = 5 shelf_life = "168h" }
 
     // stock "milk" { code = 1 check = true min = 100 register_add = "conveyor_hopper18 evend.hopper1.run(?)" spend_rate = 9.7 }
+
+    // Persisted stock not found by name or code is dropped at boot with log message.
+    // Rename: migrate "old_name" { to = "new_name" }
+    // Merge: several rules with same `to`, values are summed.
+    // migrate "milk_old" { to = "milk" }
   }
 
   menu {
@@ -40,6 +78,8 @@ engine {
       price    = 1
       scenario = "cup_drop add.water_hot(10) add.milk(10) cup_serve"
     }
+
+    // item "3" { name = "tea L" price = 7 scenario = "make_tea" vars { water = 250 } }
   }
 
   // on_boot = ["mixer_move_top", "cup_serve", "conveyor_move_cup"]
@@ -48,12 +88,42 @@ engine {
   // on_menu_error = ["money.abort", "cup_serve"]
   // on_service_begin = []
 
+  // Matching actions are logged if slower than min_us.
+  // Latency count, p50, p95, max of every matching action is sent in telemetry stat.
   profile {
     // additional escape of \ is required
     regexp     = "^(cup_|money\\.)"
     min_us     = 500
     log_format = "engine profile action=%s time=%s"
   }
+
+  // Keep trace trees of this many recent scenario runs, every Exec is traced. Debug only, default 0.
+  // Menu item runs are always traced, trace of failed vend is sent in telemetry.
+  // trace_buffer = 32
+
+  // `vender simulate` runs every menu item without hardware, prints estimated time and stock spend.
+  // Actions matching model regexp are not executed, time = duration + per_arg * first argument.
+  // Argument is in hardware units, after hw_rate. Conditions matching model return `result`.
+  simulate {
+    // model "evend\\.valve\\.pour_(hot|cold)" { duration = "500ms" per_arg = "150ms" }
+    // model "evend\\.cup\\.(dispense|ensure)" { duration = "3s" }
+    // model "evend\\.cup\\.ready" { result = true }
+  }
+
+  // Periodic maintenance. `every` default 24h, `at` aligns runs to local time of day.
+  // when="idle" (default) waits until UI is idle and locks it, when="any" only waits for engine tasks.
+  // Outcome is sent to telemetry.
+  // job "rinse" { every = "6h" at = "03:00" scenario = "mixer_rinse" }
+  // job "boiler_check" { every = "30m" scenario = "evend.valve.check_temp_hot" when = "any" }
+
+  // Hooks run scenario on event, key is `event` or `event:subject`. Payload is in variables.
+  // stock_below_min:STOCK ${code} ${value} ${min}
+  // device_offline:DEVICE, device_recovered:DEVICE ${addr}
+  // money_inserted:bill|coin ${amount} ${credit}
+  // transaction_complete:MENU_CODE ${price} ${cream} ${sugar}
+  // tele_connected
+  // hook "stock_below_min:cup" { scenario = "light_red" }
+  // hook "device_offline" { scenario = "display_problem" }
 }
 
 hardware {
@@ -69,6 +139,7 @@ hardware {
     required = true
   }
 
+  // device "cashless" { required = false }
   // device "evend.cup" { required = true }
   // device "evend.hopper5" { }
 
@@ -155,6 +226,11 @@ hardware {
       scaling_factor = 0
     }
 
+    cashless {
+      number         = 1
+      scaling_factor = 0
+    }
+
     coin {
       give_smart           = false
       dispense_timeout_sec = 0
@@ -183,6 +259,12 @@ money {
 
   // limit to over-compensate change return when exact amount is not available
   change_over_compensate = 10
+
+  // Store customer credit in `{persist.root}/money` on every change.
+  // After power loss credit is reported to telemetry as refund
+  // and, with restore_credit, offered back to customer.
+  persist        = true
+  restore_credit = true
 }
 
 persist {
@@ -211,6 +293,7 @@ ui {
     msg_menu_code_invalid        = "Code invalid"
     msg_menu_insufficient_credit = "Insufficient credit"
     msg_menu_not_available       = "Not available"
+    msg_menu_unavailable         = "N/A:%s" # unavailable menu codes, shown when idle
     msg_cream                    = "Cream"
     msg_sugar                    = "Sugar"
     msg_credit                   = "Credit"